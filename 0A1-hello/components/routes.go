@@ -0,0 +1,8 @@
+package components
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &hello{}},
+}
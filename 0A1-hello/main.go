@@ -1,56 +1,34 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"io/fs"
+
+	"github.com/suntong/go-app-demos/0A1-hello/components"
 
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/server"
 )
 
-// hello is a component that displays a simple "Hello World!". A component is a
-// customizable, independent, and reusable UI element. It is created by
-// embedding app.Compo into a struct.
-type hello struct {
-	app.Compo
-}
-
-// The Render method is where the component appearance is defined. Here, a
-// "Hello World!" is displayed as a heading.
-func (h *hello) Render() app.UI {
-	return app.H1().Text("Hello World!")
-}
+// web holds the web directory the resources are served from: the one of the
+// working directory, unless built with -tags embed (see embed.go).
+var web fs.FS
 
 // The main function is the entry point where the app is configured and started.
 // It is executed in 2 different environments: A client (the web browser) and a
 // server.
 func main() {
-	// The first thing to do is to associate the hello component with a path.
+	// server.Run associates the components with their paths, on both client
+	// and server-side, and launches the app when executed in the web browser.
 	//
-	// This is done by calling the Route() function,  which tells go-app what
-	// component to display for a given path, on both client and server-side.
-	app.Route("/", &hello{})
-
-	// Once the routes set up, the next thing to do is to either launch the app
-	// or the server that serves the app.
-	//
-	// When executed on the server-side, RunWhenOnBrowser() does nothing, which
-	// lets room for server implementation without the need for precompiling
-	// instructions.
-	app.RunWhenOnBrowser()
-
-	// Finally, launching the server that serves the app is done by using the Go
-	// standard HTTP package.
-	//
-	// The Handler is an HTTP handler that serves the client and all its
-	// required resources to make it work into a web browser. Here it is
-	// configured to handle requests with a path that starts with "/".
-	http.Handle("/", &app.Handler{
-		Name:        "Hello",
-		Description: "An Hello World! example",
+	// On the server-side, it serves the app with the Handler, an HTTP handler
+	// that serves the client and all its required resources to make it work
+	// into a web browser.
+	server.Run(server.Config{
+		Routes: components.Routes,
+		Handler: &app.Handler{
+			Name:        "Hello",
+			Description: "An Hello World! example",
+		},
+		Web: web,
 	})
-
-	log.Println("Listening on http://:8000")
-	if err := http.ListenAndServe(":8000", nil); err != nil {
-		log.Fatal(err)
-	}
 }
@@ -0,0 +1,48 @@
+package components
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// hello is a component that displays a simple "Hello World!". A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type hello struct {
+	app.Compo
+	name string
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (h *hello) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Body(
+			app.Text("Hello, "),
+			app.If(h.name != "",
+				app.Text(h.name),
+			).Else(
+				app.Text("World!"),
+			),
+		),
+		app.P().Body(
+			app.Input().
+				Type("text").
+				Value(h.name).
+				// OK: Name("name").ID("frmNameA").Attr("autocomplete", "name").
+				// OK: Name("email").ID("frmEmailA").Attr("autocomplete", "email").
+				// OK: Name("tel").ID("frmTelA").Attr("autocomplete", "tel").
+				// OK: Name("ra").Attr("autocomplete", "billing street-address").
+				// NOK: Name("ra").Attr("autocomplete", "some other string").
+				// NOK: Name("ra").Attr("autocomplete", "ContactID").
+				// NOK: Name("ra").Attr("autocomplete", "section-blue billing nickname").
+				// NOK: Name("ra").Attr("autocomplete", "billing nickname").
+				// NOK: Name("ra").Attr("autocomplete", "billing address-level4").
+				// NOK: Name("ra").Attr("autocomplete", "billing cc-csc").
+				// NOK: Name("ra").Attr("autocomplete", "one-time-code").
+				Name("ra").Attr("autocomplete", "nickname"). // NOK
+				Placeholder("What is your name?").
+				AutoFocus(true).
+				OnChange(h.ValueTo(&h.name)),
+		),
+	)
+}
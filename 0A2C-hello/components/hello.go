@@ -0,0 +1,40 @@
+package components
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// hello is a component that displays a simple "Hello World!". A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type hello struct {
+	app.Compo
+	Name string
+}
+
+func (h *hello) OnMount(ctx app.Context) {
+	app.Log("component mounted with:", h.Name)
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (h *hello) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Body(
+			app.Text("Hello, "),
+			app.If(h.Name != "",
+				app.Text(h.Name),
+			).Else(
+				app.Text("World!"),
+			),
+		),
+		app.P().Body(
+			app.Input().
+				Type("text").
+				Value(h.Name).
+				Placeholder("What is your name?").
+				AutoFocus(true).
+				OnChange(h.ValueTo(&h.Name)),
+		),
+	)
+}
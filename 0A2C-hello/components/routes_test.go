@@ -0,0 +1,24 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{
+			Path:     "/",
+			Contains: []string{"World!", `placeholder="What is your name?"`},
+			Excludes: []string{"Sam"},
+		},
+		// app.Route mounts a new hello, whose Name is empty: the name of
+		// the route is not shown.
+		{
+			Path:     "/hello",
+			Contains: []string{"World!", `placeholder="What is your name?"`},
+			Excludes: []string{"Sam"},
+		},
+	})
+}
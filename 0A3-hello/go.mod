@@ -1,7 +1,16 @@
-module github.com/suntong/go-app-demos/0A1-hello
+module github.com/suntong/go-app-demos/0A3-hello
 
 go 1.19
 
-require github.com/maxence-charriere/go-app/v9 v9.7.3
+require (
+	github.com/maxence-charriere/go-app/v9 v9.8.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
+)
 
-require github.com/google/uuid v1.3.0 // indirect
+require (
+	github.com/akrylysov/algnhsa v1.0.0 // indirect
+	github.com/aws/aws-lambda-go v1.37.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+)
+
+replace github.com/suntong/go-app-demos/pkg => ../pkg
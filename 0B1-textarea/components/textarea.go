@@ -0,0 +1,33 @@
+package components
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// appControl is a component that displays a simple "Hello World!". A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type appControl struct {
+	app.Compo
+	textStr string
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (uc *appControl) Render() app.UI {
+	return app.Div().Body(
+		app.If(uc.textStr != "",
+			app.Textarea().Text(uc.textStr).Cols(80).ReadOnly(true),
+		).Else(
+			app.P().Body(
+				app.Textarea().
+					Text(uc.textStr).
+					Spellcheck(true).
+					Style("border", "solid 1px orange;").
+					Placeholder("Paste your text").
+					AutoFocus(true).
+					OnChange(uc.ValueTo(&uc.textStr)),
+			),
+		),
+	)
+}
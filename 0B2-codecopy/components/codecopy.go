@@ -0,0 +1,96 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/clipboard"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+)
+
+// codeBlocks are the code blocks of the page, those of the HTML/JS reference
+// the demo was ported from, test/index.html.
+var codeBlocks = []string{`
+                    // Code block 1
+                    function helloWorld() {
+                        console.log("Hello, world!");
+                    }
+`, `
+                    // Code block 2
+                    for (let i = 0; i < 5; i++) {
+                        console.log(i);
+                    }
+`}
+
+// copiedFor is how long a copy button says the code was copied.
+var copiedFor = 2 * time.Second
+
+// Define component, a customizable, independent, and reusable UI
+// element. It is created by embedding app.Compo into a struct.
+type codeBlockModel struct {
+	app.Compo
+}
+
+// The Render method is where the component appearance is defined.
+func (m *codeBlockModel) Render() app.UI {
+	return app.Div().Class("code-container").Body(
+		app.Range(codeBlocks).Slice(func(i int) app.UI {
+			return app.Div().Class("code-block").Body(
+				app.Raw(`<svg class="copy-svg" stroke="currentColor" fill="none" stroke-width="2" viewBox="0 0 24 24" stroke-linecap="round" stroke-linejoin="round" height="1em" width="1em" xmlns="http://www.w3.org/2000/svg"><path d="M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2"></path><rect x="8" y="2" width="8" height="4" rx="1" ry="1"></rect></svg>`),
+				&copyButton{Code: codeBlocks[i]},
+				&codeblock.CodeBlock{
+					ID:          fmt.Sprintf("codeBlock%d", i+1),
+					Code:        codeBlocks[i],
+					Language:    "js",
+					LineNumbers: true,
+					Highlight:   []int{3},
+				},
+			)
+		}),
+	)
+}
+
+// copyButton copies the code of its code block to the clipboard. It is a
+// go-app element: click listeners added by a script to the prerendered page,
+// as the reference did, would be lost once the app replaces it.
+type copyButton struct {
+	app.Compo
+	Code string
+
+	copied bool
+}
+
+func (b *copyButton) Render() app.UI {
+	return app.Button().Class("copy-button").
+		Body(
+			app.If(b.copied,
+				app.I().Text("Copied"),
+			).Else(
+				app.Text("Copy code"),
+			),
+		).
+		OnClick(b.onClick)
+}
+
+func (b *copyButton) onClick(ctx app.Context, e app.Event) {
+	text := codeblock.Dedent(b.Code)
+	ctx.Async(func() {
+		if err := clipboard.WriteText(ctx, text); err != nil {
+			log.Println(err)
+			return
+		}
+		ctx.Dispatch(func(ctx app.Context) {
+			b.copied = true
+		})
+		// A timer, as the setTimeout of the reference, rather than
+		// ctx.After, which would keep the async work of the button pending
+		// until it is over.
+		time.AfterFunc(copiedFor, func() {
+			ctx.Dispatch(func(ctx app.Context) {
+				b.copied = false
+			})
+		})
+	})
+}
@@ -0,0 +1,75 @@
+//go:build js && wasm
+
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+)
+
+// The tests of this file run the app in Node.js, on the DOM of test/dom.js:
+//
+//	make test-wasm
+
+func TestCopyButtons(t *testing.T) {
+	defer func(d time.Duration) { copiedFor = d }(copiedFor)
+	copiedFor = 50 * time.Millisecond
+
+	m := &codeBlockModel{}
+	d := app.NewClientTester(m)
+	defer d.Close()
+
+	buttons := m.JSValue().Call("querySelectorAll", ".copy-button")
+	if n := buttons.Length(); n != len(codeBlocks) {
+		t.Fatalf("%d copy buttons, want %d", n, len(codeBlocks))
+	}
+	for i := range codeBlocks {
+		b := buttons.Index(i)
+		if got := b.Get("innerHTML").String(); got != "Copy code" {
+			t.Fatalf("button %d = %q before the click, want %q", i, got, "Copy code")
+		}
+
+		b.Call("click")
+		d.Consume()
+		if got := b.Get("innerHTML").String(); got != "<i>Copied</i>" {
+			t.Errorf("button %d = %q after the click, want %q", i, got, "<i>Copied</i>")
+		}
+		if got, want := app.Window().Get("testClipboard").String(), codeblock.Dedent(codeBlocks[i]); got != want {
+			t.Errorf("button %d copied %q, want %q", i, got, want)
+		}
+		for j := range codeBlocks {
+			if j != i && buttons.Index(j).Get("innerHTML").String() != "Copy code" {
+				t.Errorf("button %d changed with the click of button %d", j, i)
+			}
+		}
+
+		time.Sleep(2 * copiedFor)
+		d.Consume()
+		if got := b.Get("innerHTML").String(); got != "Copy code" {
+			t.Errorf("button %d = %q after %v, want %q", i, got, copiedFor, "Copy code")
+		}
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	m := &codeBlockModel{}
+	d := app.NewClientTester(m)
+	defer d.Close()
+
+	// As the reference did, the code of a button is the one of its next
+	// sibling.
+	buttons := m.JSValue().Call("querySelectorAll", ".copy-button")
+	for i := range codeBlocks {
+		code := buttons.Index(i).Get("nextElementSibling").Call("querySelector", "code")
+		if !code.Truthy() {
+			t.Fatalf("code block %d: no code element after the button", i)
+		}
+		if got := code.Get("textContent").String(); !strings.Contains(got, "// Code block") {
+			t.Errorf("code block %d = %q", i, got)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !js
+
+package components
+
+import (
+	"html"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{
+			Path: "/",
+			Contains: []string{
+				`class="code-block"`,
+				`class="copy-button"`,
+				`data-lang="JavaScript"`,
+				`<span class="line hl"><span class="ln">3</span>`,
+				`<span class="nx">helloWorld</span>`,
+				`<code id="codeBlock2">`,
+			},
+		},
+	})
+}
+
+// TestReference checks the port against the HTML/JS reference it was ported
+// from: the same code blocks, in elements of the same classes.
+func TestReference(t *testing.T) {
+	ref, err := os.ReadFile("../test/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := regexp.MustCompile(`(?s)<code>(.*?)</code>`).FindAllStringSubmatch(string(ref), -1)
+	if len(codes) != len(codeBlocks) {
+		t.Fatalf("%d code blocks, the reference has %d", len(codeBlocks), len(codes))
+	}
+	for i, m := range codes {
+		if want := html.UnescapeString(strings.TrimSpace(m[1])); strings.TrimSpace(codeBlocks[i]) != want {
+			t.Errorf("code block %d = %q, the reference has %q", i+1, codeBlocks[i], want)
+		}
+	}
+
+	page := servertest.Page{Path: "/"}
+	for _, m := range regexp.MustCompile(`class="([^"]+)"`).FindAllStringSubmatch(string(ref), -1) {
+		// The second class of the copy icon is ignored by the browsers, and
+		// dropped by the port.
+		if m[1] != "h-4 w-4" {
+			page.Contains = append(page.Contains, `class="`+m[1]+`"`)
+		}
+	}
+	servertest.Run(t, Routes, []servertest.Page{page})
+}
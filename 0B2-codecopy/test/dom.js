@@ -0,0 +1,462 @@
+// dom.js is a lightweight DOM for the js/wasm tests of the components, which
+// go_js_wasm_exec loads in Node.js before the test binary. It implements just
+// enough of the document for go-app to mount the components, for the tests to
+// click them, and for package clipboard to copy with execCommand("copy"), the
+// text copied being kept in globalThis.testClipboard.
+"use strict";
+
+const voidElements = new Set([
+  "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta",
+  "source", "track", "wbr",
+]);
+
+const svgNS = "http://www.w3.org/2000/svg";
+
+class Node {
+  constructor(nodeType) {
+    this.nodeType = nodeType;
+    this.parentNode = null;
+    this.childNodes = [];
+  }
+
+  get firstChild() {
+    return this.childNodes[0] || null;
+  }
+
+  get lastChild() {
+    return this.childNodes[this.childNodes.length - 1] || null;
+  }
+
+  get nextSibling() {
+    return this.sibling(1);
+  }
+
+  get previousSibling() {
+    return this.sibling(-1);
+  }
+
+  sibling(offset) {
+    if (!this.parentNode) {
+      return null;
+    }
+    const siblings = this.parentNode.childNodes;
+    return siblings[siblings.indexOf(this) + offset] || null;
+  }
+
+  get children() {
+    return this.childNodes.filter((n) => n.nodeType === 1);
+  }
+
+  get firstElementChild() {
+    return this.children[0] || null;
+  }
+
+  get nextElementSibling() {
+    let n = this.nextSibling;
+    while (n && n.nodeType !== 1) {
+      n = n.nextSibling;
+    }
+    return n;
+  }
+
+  appendChild(child) {
+    return this.insertBefore(child, null);
+  }
+
+  insertBefore(child, ref) {
+    if (child.parentNode) {
+      child.parentNode.removeChild(child);
+    }
+    const i = ref ? this.childNodes.indexOf(ref) : -1;
+    if (i < 0) {
+      this.childNodes.push(child);
+    } else {
+      this.childNodes.splice(i, 0, child);
+    }
+    child.parentNode = this;
+    return child;
+  }
+
+  removeChild(child) {
+    const i = this.childNodes.indexOf(child);
+    if (i < 0) {
+      throw new DOMError("NotFoundError", "the node is not a child of this node");
+    }
+    this.childNodes.splice(i, 1);
+    child.parentNode = null;
+    return child;
+  }
+
+  replaceChild(child, old) {
+    this.insertBefore(child, old);
+    return this.removeChild(old);
+  }
+
+  get textContent() {
+    return this.childNodes.map((n) => n.textContent).join("");
+  }
+
+  set textContent(v) {
+    this.childNodes.forEach((n) => (n.parentNode = null));
+    this.childNodes = [];
+    if (v !== "") {
+      this.appendChild(new Text(String(v)));
+    }
+  }
+
+  get isConnected() {
+    let n = this;
+    while (n.parentNode) {
+      n = n.parentNode;
+    }
+    return n === globalThis.document;
+  }
+}
+
+class Text extends Node {
+  constructor(data) {
+    super(3);
+    this.nodeValue = data;
+  }
+
+  get data() {
+    return this.nodeValue;
+  }
+
+  get textContent() {
+    return this.nodeValue;
+  }
+
+  set textContent(v) {
+    this.nodeValue = String(v);
+  }
+
+  get outerHTML() {
+    return escape(this.nodeValue);
+  }
+}
+
+class Element extends Node {
+  constructor(tag, namespaceURI) {
+    super(1);
+    this.namespaceURI = namespaceURI || "http://www.w3.org/1999/xhtml";
+    this.localName = namespaceURI === svgNS ? tag : tag.toLowerCase();
+    this.tagName = namespaceURI === svgNS ? tag : tag.toUpperCase();
+    this.attrs = new Map();
+    this.listeners = new Map();
+    this.style = {};
+    this.value = "";
+  }
+
+  setAttribute(name, value) {
+    this.attrs.set(name, String(value));
+  }
+
+  getAttribute(name) {
+    return this.attrs.has(name) ? this.attrs.get(name) : null;
+  }
+
+  hasAttribute(name) {
+    return this.attrs.has(name);
+  }
+
+  removeAttribute(name) {
+    this.attrs.delete(name);
+  }
+
+  get id() {
+    return this.getAttribute("id") || "";
+  }
+
+  set id(v) {
+    this.setAttribute("id", v);
+  }
+
+  get className() {
+    return this.getAttribute("class") || "";
+  }
+
+  set className(v) {
+    this.setAttribute("class", v);
+  }
+
+  get classList() {
+    const classes = this.className.split(/\s+/).filter((c) => c);
+    return {
+      contains: (c) => classes.includes(c),
+    };
+  }
+
+  addEventListener(type, fn) {
+    if (!this.listeners.has(type)) {
+      this.listeners.set(type, []);
+    }
+    this.listeners.get(type).push(fn);
+  }
+
+  removeEventListener(type, fn) {
+    const fns = this.listeners.get(type) || [];
+    const i = fns.indexOf(fn);
+    if (i >= 0) {
+      fns.splice(i, 1);
+    }
+  }
+
+  // dispatchEvent calls the listeners of e on the element, then on its
+  // ancestors when e bubbles.
+  dispatchEvent(e) {
+    e.target = e.target || this;
+    for (let n = this; n && !e.propagationStopped; n = e.bubbles ? n.parentNode : null) {
+      e.currentTarget = n;
+      for (const fn of (n.listeners && n.listeners.get(e.type)) || []) {
+        fn.call(n, e);
+      }
+    }
+    return !e.defaultPrevented;
+  }
+
+  click() {
+    this.dispatchEvent(new Event("click", { bubbles: true }));
+  }
+
+  focus() {}
+
+  blur() {}
+
+  select() {
+    globalThis.document.selection = this;
+  }
+
+  get innerHTML() {
+    return this.childNodes.map((n) => n.outerHTML).join("");
+  }
+
+  set innerHTML(html) {
+    this.textContent = "";
+    parse(html, this);
+  }
+
+  get outerHTML() {
+    let attrs = "";
+    for (const [k, v] of this.attrs) {
+      attrs += v === "" ? ` ${k}` : ` ${k}="${escape(v)}"`;
+    }
+    if (voidElements.has(this.localName)) {
+      return `<${this.localName}${attrs}>`;
+    }
+    return `<${this.localName}${attrs}>${this.innerHTML}</${this.localName}>`;
+  }
+
+  get innerText() {
+    return this.textContent;
+  }
+
+  set innerText(v) {
+    this.textContent = v;
+  }
+
+  // matches supports the compound selectors of a tag name, an ID and
+  // classes, such as button.copy-button.
+  matches(selector) {
+    const m = /^([\w-]*)((?:[#.][\w-]+)*)$/.exec(selector.trim());
+    if (!m) {
+      throw new DOMError("SyntaxError", `unsupported selector ${selector}`);
+    }
+    if (m[1] && m[1].toLowerCase() !== this.localName.toLowerCase()) {
+      return false;
+    }
+    for (const part of m[2].match(/[#.][\w-]+/g) || []) {
+      const name = part.slice(1);
+      if (part[0] === "#" ? this.id !== name : !this.classList.contains(name)) {
+        return false;
+      }
+    }
+    return true;
+  }
+
+  querySelectorAll(selector) {
+    const found = [];
+    const walk = (n) => {
+      for (const c of n.children) {
+        if (c.matches(selector)) {
+          found.push(c);
+        }
+        walk(c);
+      }
+    };
+    walk(this);
+    return found;
+  }
+
+  querySelector(selector) {
+    return this.querySelectorAll(selector)[0] || null;
+  }
+}
+
+class Document extends Element {
+  constructor() {
+    super("#document");
+    this.nodeType = 9;
+    this.title = "";
+    this.selection = null;
+    this.documentElement = this.appendChild(this.createElement("html"));
+    this.head = this.documentElement.appendChild(this.createElement("head"));
+    this.body = this.documentElement.appendChild(this.createElement("body"));
+  }
+
+  createElement(tag) {
+    return new Element(tag);
+  }
+
+  createElementNS(namespaceURI, tag) {
+    return new Element(tag, namespaceURI);
+  }
+
+  createTextNode(data) {
+    return new Text(String(data));
+  }
+
+  getElementById(id) {
+    return this.querySelector("#" + id);
+  }
+
+  queryCommandSupported(command) {
+    return command === "copy";
+  }
+
+  // execCommand("copy") copies the value of the selected textarea, as the
+  // browsers do, into globalThis.testClipboard.
+  execCommand(command) {
+    if (command !== "copy" || !this.selection || !this.selection.isConnected) {
+      return false;
+    }
+    globalThis.testClipboard = this.selection.value;
+    return true;
+  }
+}
+
+class Event {
+  constructor(type, init = {}) {
+    this.type = type;
+    this.bubbles = !!init.bubbles;
+    this.defaultPrevented = false;
+    this.propagationStopped = false;
+    this.target = null;
+    this.currentTarget = null;
+  }
+
+  preventDefault() {
+    this.defaultPrevented = true;
+  }
+
+  stopPropagation() {
+    this.propagationStopped = true;
+  }
+}
+
+class DOMError extends Error {
+  constructor(name, message) {
+    super(message);
+    this.name = name;
+  }
+}
+
+class Storage {
+  constructor() {
+    this.items = new Map();
+  }
+
+  get length() {
+    return this.items.size;
+  }
+
+  key(i) {
+    return [...this.items.keys()][i] ?? null;
+  }
+
+  getItem(k) {
+    return this.items.has(k) ? this.items.get(k) : null;
+  }
+
+  setItem(k, v) {
+    this.items.set(k, String(v));
+  }
+
+  removeItem(k) {
+    this.items.delete(k);
+  }
+
+  clear() {
+    this.items.clear();
+  }
+}
+
+const entities = { amp: "&", lt: "<", gt: ">", quot: '"', apos: "'", nbsp: " " };
+
+function unescape(s) {
+  return s.replace(/&(#x[0-9a-f]+|#[0-9]+|\w+);/gi, (m, e) => {
+    if (e[0] === "#") {
+      return String.fromCodePoint(e[1] === "x" || e[1] === "X" ? parseInt(e.slice(2), 16) : parseInt(e.slice(1), 10));
+    }
+    return entities[e] ?? m;
+  });
+}
+
+function escape(s) {
+  return s.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;");
+}
+
+// parse appends the nodes of html to parent. It handles the HTML go-app and
+// the components produce: elements, attributes, text and comments.
+function parse(html, parent) {
+  const root = parent;
+  const tokens = /<!--[\s\S]*?-->|<\/([\w:-]+)\s*>|<([\w:-]+)((?:\s+[\w:-]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+))?)*)\s*(\/?)>|[^<]+|</g;
+  const attr = /([\w:-]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+)))?/g;
+  let m;
+  while ((m = tokens.exec(html))) {
+    const [token, closing, tag, attrs, selfClosing] = m;
+    if (token.startsWith("<!--")) {
+      continue;
+    }
+    if (closing) {
+      for (let n = parent; n !== root; n = n.parentNode) {
+        if (n.localName === closing) {
+          parent = n.parentNode;
+          break;
+        }
+      }
+      continue;
+    }
+    if (!tag) {
+      parent.appendChild(new Text(unescape(token)));
+      continue;
+    }
+
+    const ns = tag === "svg" || parent.namespaceURI === svgNS ? svgNS : "";
+    const e = ns ? new Element(tag, ns) : new Element(tag);
+    let a;
+    attr.lastIndex = 0;
+    while ((a = attr.exec(attrs))) {
+      if (!e.hasAttribute(a[1])) {
+        e.setAttribute(a[1], unescape(a[2] ?? a[3] ?? a[4] ?? ""));
+      }
+    }
+    parent.appendChild(e);
+    if (!selfClosing && !voidElements.has(e.localName)) {
+      parent = e;
+    }
+  }
+}
+
+globalThis.window = globalThis;
+globalThis.document = new Document();
+globalThis.location = { href: "http://localhost:8000/" };
+globalThis.history = { pushState() {}, replaceState() {} };
+globalThis.localStorage = new Storage();
+globalThis.sessionStorage = new Storage();
+globalThis.isSecureContext = false;
+globalThis.testClipboard = "";
+if (!globalThis.navigator) {
+  globalThis.navigator = {};
+}
+globalThis.Event = Event;
@@ -1 +0,0 @@
-../test/script.js
\ No newline at end of file
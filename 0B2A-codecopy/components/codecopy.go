@@ -0,0 +1,140 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+	"github.com/suntong/go-app-demos/pkg/sse"
+)
+
+////////////////////////////////////////
+// Credit:
+// https://github.com/maxence-charriere/go-app/issues/859#issuecomment-1677198131
+// https://github.com/maxence-charriere/go-app/issues/872#issuecomment-1677725579
+////////////////////////////////////////
+
+// Define component, a customizable, independent, and reusable UI
+// element. It is created by embedding app.Compo into a struct.
+type codeBlockModel struct {
+	app.Compo
+	code   []string
+	status string
+}
+
+// OnMount subscribes to the code snippets the server publishes, which the
+// client appends to the code blocks on the UI goroutine.
+func (m *codeBlockModel) OnMount(ctx app.Context) {
+	c := &sse.Client{
+		URL:    SnippetsPath,
+		Events: []string{snippetEvent},
+		OnEvent: func(e sse.Event) {
+			ctx.Dispatch(func(ctx app.Context) {
+				m.addSnippet(e)
+			})
+		},
+		OnStatus: func(s sse.Status, retry time.Duration) {
+			ctx.Dispatch(func(ctx app.Context) {
+				m.status = s.String()
+				if s == sse.Reconnecting {
+					m.status = fmt.Sprintf("%s in %v", s, retry)
+				}
+			})
+		},
+	}
+	ctx.Async(func() {
+		if err := c.Run(ctx); err != nil && err != ctx.Err() {
+			log.Println(err)
+		}
+	})
+}
+
+// addSnippet adds the code of a snippet event. The snippets are numbered from
+// 1: those replayed by a restarted server replace the ones received before.
+func (m *codeBlockModel) addSnippet(e sse.Event) {
+	if n, err := strconv.Atoi(e.ID); err == nil && n >= 1 && n <= len(m.code) {
+		m.code[n-1] = e.Data
+		return
+	}
+	m.code = append(m.code, e.Data)
+}
+
+// The Render method is where the component appearance is defined.
+func (m *codeBlockModel) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Text("H1"),
+		app.H4().Text("H4"),
+		app.P().Class("status").Text("Snippets: "+m.statusText()),
+
+		app.Div().Class("code-container").Body(
+			app.Range(m.code).Slice(func(i int) app.UI {
+				id := len(m.code) - 1 - i
+				//id = i
+				block := &codeblock.CodeBlock{
+					ID:       fmt.Sprintf("codeBlock%02d", id),
+					Code:     m.code[id],
+					Language: "js",
+				}
+				return app.Div().Class("code-block").Body(
+					copySVG(),
+					&CopyButton{Formats: copyFormats[id%len(copyFormats)], text: "Copy code", from: block},
+					block,
+				)
+			}),
+		),
+	)
+}
+
+func (m *codeBlockModel) statusText() string {
+	if m.status == "" {
+		return sse.Connecting.String()
+	}
+	return m.status
+}
+
+func copySVG() app.UI {
+	return app.Raw(`<svg stroke="currentColor" fill="none" stroke-width="2" viewBox="0 0 24 24" stroke-linecap="round" stroke-linejoin="round" class="copy-svg h-4 w-4" height="1em" width="1em" xmlns="http://www.w3.org/2000/svg"><path d="M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2"></path><rect x="8" y="2" width="8" height="4" rx="1" ry="1"></rect></svg>`)
+}
+
+// copyFormats are the formats the code blocks are copied in, in turn: pasted
+// into a document, both carry the highlighting; into a chat tool, the second
+// one is a Markdown code block.
+var copyFormats = []codeblock.Formats{codeblock.HTML, codeblock.HTML | codeblock.Markdown}
+
+type CopyButton struct {
+	app.Compo
+	// The formats the code is copied in, on top of plain text. Browsers only
+	// copy the plain text out of secure contexts.
+	Formats codeblock.Formats
+
+	from *codeblock.CodeBlock
+	text string
+}
+
+func (cb *CopyButton) Render() app.UI {
+	return app.Button().Class("copy-button").
+		Title("Copy as " + cb.Formats.String()).
+		Text(cb.text).
+		OnClick(cb.onClick)
+}
+
+func (cb *CopyButton) onClick(ctx app.Context, e app.Event) {
+	ctx.Async(func() {
+		err := cb.from.Copy(ctx, cb.Formats)
+		ctx.Dispatch(func(ctx app.Context) {
+			cb.text = "Copied"
+			if err != nil {
+				log.Println(err)
+				cb.text = "Copy failed"
+			}
+			ctx.After(2*time.Second, cb.revertText)
+		})
+	})
+}
+
+func (cb *CopyButton) revertText(ctx app.Context) {
+	cb.text = "Copy code"
+}
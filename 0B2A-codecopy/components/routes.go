@@ -0,0 +1,13 @@
+package components
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &codeBlockModel{}},
+}
+
+// Endpoints are the server endpoints the demo components call.
+var Endpoints = []server.Endpoint{
+	{Pattern: SnippetsPath, Handler: Snippets()},
+}
@@ -0,0 +1,13 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{Path: "/", Contains: []string{"<h1>H1</h1>", `class="code-container"`, "Snippets: connecting"}},
+	})
+}
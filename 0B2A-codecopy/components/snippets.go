@@ -0,0 +1,47 @@
+package components
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/suntong/go-app-demos/pkg/sse"
+)
+
+// SnippetsPath is the path of the stream of code snippets the server
+// publishes.
+const SnippetsPath = "/snippets"
+
+// snippetEvent is the name of the events carrying a code snippet.
+const snippetEvent = "snippet"
+
+var snippets = []string{`
+                    // Code block 1
+                    function helloWorld() {
+                        console.log("Hello, world!");
+                    }
+`, `
+                    // Code block 2
+                    for (let i = 0; i < 5; i++) {
+                        console.log(i);
+                    }
+`}
+
+// Snippets returns the handler streaming the code snippets as server-sent
+// events. They are published one per second once the first page subscribes,
+// and replayed to the pages subscribing later.
+func Snippets() http.Handler {
+	b := sse.NewBroker(len(snippets))
+	var once sync.Once
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			go func() {
+				for _, s := range snippets {
+					time.Sleep(time.Second)
+					b.Publish(snippetEvent, s)
+				}
+			}()
+		})
+		b.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,44 @@
+package components
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnippets(t *testing.T) {
+	s := httptest.NewServer(Snippets())
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	// Both snippets are published, as multi-line data.
+	r := bufio.NewReader(res.Body)
+	var lines []string
+	for len(lines) < 2 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, "// Code block") {
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, "data: ")))
+		}
+	}
+	if lines[0] != "// Code block 1" || lines[1] != "// Code block 2" {
+		t.Errorf("snippets = %q", lines)
+	}
+}
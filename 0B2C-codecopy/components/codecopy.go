@@ -0,0 +1,63 @@
+package components
+
+import (
+	"log"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/clipboard"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+)
+
+// Define component, a customizable, independent, and reusable UI
+// element. It is created by embedding app.Compo into a struct.
+type codeBlockModel struct {
+	app.Compo
+	code   string
+	copied bool
+}
+
+func (m *codeBlockModel) OnInit() {
+	m.code = `
+                    // Code block 1
+                    function helloWorld() {
+                        console.log("Hello, world!");
+                    }
+`
+}
+
+// The Render method is where the component appearance is defined.
+func (m *codeBlockModel) Render() app.UI {
+	return app.Div().Class("code-container").Body(
+		app.Div().Class("code-block").Body(
+			app.Raw(`<svg class="copy-svg" stroke="currentColor" fill="none" stroke-width="2" viewBox="0 0 24 24" stroke-linecap="round" stroke-linejoin="round" class="h-4 w-4" height="1em" width="1em" xmlns="http://www.w3.org/2000/svg"><path d="M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2"></path><rect x="8" y="2" width="8" height="4" rx="1" ry="1"></rect></svg>`),
+			app.Button().Class("copy-button").
+				Text(m.buttonText()).
+				OnClick(m.onButtonClicked),
+			&codeblock.CodeBlock{Code: m.code, Language: "js"},
+		),
+	)
+}
+
+func (m *codeBlockModel) buttonText() string {
+	if m.copied {
+		return "Copied"
+	}
+	return "Copy code"
+}
+
+func (m *codeBlockModel) onButtonClicked(ctx app.Context, e app.Event) {
+	text := codeblock.Dedent(m.code)
+	ctx.Async(func() {
+		if err := clipboard.WriteText(ctx, text); err != nil {
+			log.Println(err)
+			return
+		}
+		ctx.Dispatch(func(ctx app.Context) {
+			m.copied = true
+		})
+		ctx.After(2*time.Second, func(ctx app.Context) {
+			m.copied = false
+		})
+	})
+}
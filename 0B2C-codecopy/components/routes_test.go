@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{
+			Path: "/",
+			Contains: []string{
+				`class="copy-button"`,
+				"Copy code",
+				`class="codeblock-lang"`,
+				`<span class="kd">function</span> <span class="nx">helloWorld</span>`,
+			},
+		},
+	})
+}
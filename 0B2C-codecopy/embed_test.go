@@ -0,0 +1,19 @@
+package main
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+// testWeb is the web directory, as embed.go embeds it.
+//
+//go:embed web
+var testWeb embed.FS
+
+// TestEmbed checks that the binary built with -tags embed carries the
+// resources the app references.
+func TestEmbed(t *testing.T) {
+	servertest.Resources(t, config(testWeb).Handler, testWeb)
+}
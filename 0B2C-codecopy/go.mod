@@ -1,10 +1,18 @@
-module project
+module github.com/suntong/go-app-demos/0B2C-codecopy
 
 go 1.19
 
 require (
-	github.com/maxence-charriere/go-app/v9 v9.7.3
-	github.com/mlctrez/imgtofactbp v1.0.0
+	github.com/maxence-charriere/go-app/v9 v9.8.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
 )
 
-require github.com/google/uuid v1.3.0 // indirect
+require (
+	github.com/akrylysov/algnhsa v1.0.0 // indirect
+	github.com/alecthomas/chroma/v2 v2.14.0 // indirect
+	github.com/aws/aws-lambda-go v1.37.0 // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+)
+
+replace github.com/suntong/go-app-demos/pkg => ../pkg
@@ -1 +0,0 @@
-../../0B2-codecopy/test/script.js
\ No newline at end of file
@@ -0,0 +1,126 @@
+package components
+
+import (
+	"log"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B2D-codecopy/snippet"
+)
+
+// shareForm is the form sharing a snippet, which navigates to the page of the
+// snippet once stored.
+type shareForm struct {
+	app.Compo
+	title    string
+	language string
+	lifetime string
+	code     string
+
+	sharing bool
+	err     string
+}
+
+func (f *shareForm) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Text("Share a snippet"),
+		app.Form().Class("share-form").OnSubmit(f.onSubmit).Body(
+			app.Input().
+				Type("text").
+				Placeholder("Title").
+				MaxLength(snippet.MaxTitle).
+				Value(f.title).
+				OnChange(f.ValueTo(&f.title)),
+			app.Div().Class("share-options").Body(
+				app.Label().Body(
+					app.Text("Language "),
+					app.Select().OnChange(f.ValueTo(&f.language)).Body(
+						option("", "Guess from the code", f.language),
+						app.Range(snippet.Languages).Slice(func(i int) app.UI {
+							l := snippet.Languages[i]
+							return option(l, l, f.language)
+						}),
+					),
+				),
+				app.Label().Body(
+					app.Text("Expires "),
+					app.Select().OnChange(f.ValueTo(&f.lifetime)).Body(
+						app.Range(snippet.Lifetimes).Slice(func(i int) app.UI {
+							l := snippet.Lifetimes[i]
+							return option(l.Label, l.Label, f.lifetimeLabel())
+						}),
+					),
+				),
+			),
+			app.Textarea().
+				Placeholder("Code").
+				Rows(16).
+				Required(true).
+				Text(f.code).
+				OnChange(f.ValueTo(&f.code)),
+			app.If(f.err != "",
+				app.P().Class("error").Text(f.err),
+			),
+			submitButton(f.sharing),
+		),
+	)
+}
+
+// option returns the option of value, selected when it is the one picked.
+// Boolean attributes are only set when true, as the prerendering writes the
+// false ones as "false", which the browser reads as set.
+func option(value, text, picked string) app.UI {
+	o := app.Option().Value(value).Text(text)
+	if value == picked {
+		o = o.Selected(true)
+	}
+	return o
+}
+
+func submitButton(sharing bool) app.UI {
+	b := app.Button().Type("submit").Text("Share")
+	if sharing {
+		b = b.Disabled(true)
+	}
+	return b
+}
+
+// lifetimeLabel returns the label of the lifetime picked, the first one by
+// default.
+func (f *shareForm) lifetimeLabel() string {
+	if f.lifetime == "" {
+		return snippet.Lifetimes[0].Label
+	}
+	return f.lifetime
+}
+
+func (f *shareForm) request() snippet.Request {
+	r := snippet.Request{Title: f.title, Language: f.language, Code: f.code}
+	for _, l := range snippet.Lifetimes {
+		if l.Label == f.lifetimeLabel() {
+			r.Lifetime = l.Duration
+		}
+	}
+	return r
+}
+
+func (f *shareForm) onSubmit(ctx app.Context, e app.Event) {
+	e.PreventDefault()
+	r := f.request()
+	if err := r.Validate(); err != nil {
+		f.err = err.Error()
+		return
+	}
+	f.sharing, f.err = true, ""
+	ctx.Async(func() {
+		s, err := snippet.Client{}.Create(ctx, r)
+		ctx.Dispatch(func(ctx app.Context) {
+			f.sharing = false
+			if err != nil {
+				log.Println(err)
+				f.err = err.Error()
+				return
+			}
+			ctx.Navigate(snippet.Page(s.ID))
+		})
+	})
+}
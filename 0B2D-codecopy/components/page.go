@@ -0,0 +1,143 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B2D-codecopy/snippet"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+)
+
+// Snippets, set on the server-side, is where the snippet pages are
+// prerendered from. Prerendering does not count a view: the app fetching the
+// snippet once loaded does.
+var Snippets interface {
+	Get(id string) (snippet.Snippet, error)
+}
+
+// snippetPage displays the snippet of its path, /s/{id}, with its copy
+// button.
+type snippetPage struct {
+	app.Compo
+	snippet snippet.Snippet
+	err     error
+}
+
+func (p *snippetPage) OnPreRender(ctx app.Context) {
+	if Snippets == nil {
+		return
+	}
+	p.snippet, p.err = Snippets.Get(snippet.PageID(ctx.Page().URL().Path))
+	p.setTitle(ctx)
+}
+
+// OnNav fetches the snippet through the API, which counts the view.
+func (p *snippetPage) OnNav(ctx app.Context) {
+	id := snippet.PageID(ctx.Page().URL().Path)
+	ctx.Async(func() {
+		s, err := snippet.Client{}.Get(ctx, id)
+		ctx.Dispatch(func(ctx app.Context) {
+			if err != nil && !errors.Is(err, snippet.ErrNotFound) {
+				log.Println(err)
+			}
+			p.snippet, p.err = s, err
+			p.setTitle(ctx)
+		})
+	})
+}
+
+func (p *snippetPage) setTitle(ctx app.Context) {
+	if p.err == nil {
+		ctx.Page().SetTitle(p.snippet.Title)
+	}
+}
+
+func (p *snippetPage) Render() app.UI {
+	s := p.snippet
+	switch {
+	case p.err != nil:
+		return app.Div().Body(
+			app.H1().Text("Snippet"),
+			app.P().Class("error").Text(p.err.Error()),
+			app.A().Href("/").Text("Share a snippet"),
+		)
+	case s.ID == "":
+		return app.Div().Body(
+			app.H1().Text("Snippet"),
+			app.P().Class("status").Text("Loading..."),
+		)
+	}
+
+	title := s.Title
+	if title == "" {
+		title = "Untitled snippet"
+	}
+	return app.Div().Body(
+		app.H1().Text(title),
+		app.P().Class("status").Text(status(s, time.Now())),
+		app.Div().Class("code-block").Body(
+			&copyButton{Code: s.Code, Language: s.Language},
+			&codeblock.CodeBlock{ID: "snippet", Code: s.Code, Language: s.Language, LineNumbers: true},
+		),
+		app.P().Body(
+			app.A().Href(snippet.Raw(s.ID)).Attr("download", s.Filename()).Text("Download"),
+			app.Text(" · "),
+			app.A().Href("/").Text("Share a snippet"),
+		),
+	)
+}
+
+// status returns the view count and expiry of s at now.
+func status(s snippet.Snippet, now time.Time) string {
+	views := strconv.Itoa(s.Views) + " views"
+	if s.Views == 1 {
+		views = "1 view"
+	}
+	if s.Expires.IsZero() {
+		return views + ", never expires"
+	}
+	left := s.Expires.Sub(now).Round(time.Minute)
+	if left < time.Minute {
+		return views + ", expires in less than a minute"
+	}
+	return fmt.Sprintf("%s, expires in %v", views, left)
+}
+
+// copyButton copies the code of a snippet, along with its highlighting as
+// HTML where the browser supports it.
+type copyButton struct {
+	app.Compo
+	Code     string
+	Language string
+
+	text string
+}
+
+func (b *copyButton) Render() app.UI {
+	text := b.text
+	if text == "" {
+		text = "Copy code"
+	}
+	return app.Button().Class("copy-button").Text(text).OnClick(b.onClick)
+}
+
+func (b *copyButton) onClick(ctx app.Context, e app.Event) {
+	block := &codeblock.CodeBlock{Code: b.Code, Language: b.Language}
+	ctx.Async(func() {
+		err := block.Copy(ctx, codeblock.HTML)
+		ctx.Dispatch(func(ctx app.Context) {
+			b.text = "Copied"
+			if err != nil {
+				log.Println(err)
+				b.text = "Copy failed"
+			}
+			ctx.After(2*time.Second, func(ctx app.Context) {
+				b.text = ""
+			})
+		})
+	})
+}
@@ -0,0 +1,12 @@
+package components
+
+import (
+	"github.com/suntong/go-app-demos/0B2D-codecopy/snippet"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &shareForm{}},
+	{Path: snippet.PageRoute, Compo: &snippetPage{}},
+}
@@ -0,0 +1,68 @@
+package components
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B2D-codecopy/snippet"
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+type snippets map[string]snippet.Snippet
+
+func (s snippets) Get(id string) (snippet.Snippet, error) {
+	if sn, ok := s[id]; ok {
+		return sn, nil
+	}
+	return snippet.Snippet{}, snippet.ErrNotFound
+}
+
+func TestRoutes(t *testing.T) {
+	Snippets = snippets{
+		"aB3dE6gH": {ID: "aB3dE6gH", Title: "Hello", Language: "Go", Code: "fmt.Println(\"hello\")", Views: 3},
+	}
+	defer func() { Snippets = nil }()
+
+	servertest.Run(t, Routes, []servertest.Page{
+		{Path: "/", Contains: []string{"<h1>Share a snippet</h1>", ">1 hour</option>", `<option value="1 week">1 week</option>`, "<textarea"}},
+		{Path: "/s/aB3dE6gH", Contains: []string{
+			"<title>Hello</title>",
+			"<h1>Hello</h1>",
+			"3 views, never expires",
+			`class="copy-button"`,
+			`<span class="nf">Println</span>`,
+			`href="/api/snippets/aB3dE6gH/raw"`,
+		}},
+		{Path: "/s/unknown", Contains: []string{"snippet not found"}},
+	})
+}
+
+func TestOption(t *testing.T) {
+	// The attributes are written in no particular order.
+	picked := regexp.MustCompile(`^<option (value="1 hour" selected|selected value="1 hour")>1 hour</option>$`)
+	if got := app.HTMLString(option("1 hour", "1 hour", "1 hour")); !picked.MatchString(got) {
+		t.Errorf("picked option = %s, want it selected", got)
+	}
+	if got, want := app.HTMLString(option("1 week", "1 week", "1 hour")), `<option value="1 week">1 week</option>`; got != want {
+		t.Errorf("option = %s, want %s", got, want)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		s    snippet.Snippet
+		want string
+	}{
+		{snippet.Snippet{Views: 1}, "1 view, never expires"},
+		{snippet.Snippet{Expires: now.Add(90 * time.Minute)}, "0 views, expires in 1h30m0s"},
+		{snippet.Snippet{Views: 2, Expires: now.Add(time.Second)}, "2 views, expires in less than a minute"},
+	}
+	for _, test := range tests {
+		if got := status(test.s, now); got != test.want {
+			t.Errorf("status(%+v) = %q, want %q", test.s, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/suntong/go-app-demos/0B2D-codecopy
+
+go 1.19
+
+require (
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/maxence-charriere/go-app/v9 v9.8.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
+	go.etcd.io/bbolt v1.3.8
+)
+
+require (
+	github.com/akrylysov/algnhsa v1.0.0 // indirect
+	github.com/aws/aws-lambda-go v1.37.0 // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
+)
+
+replace github.com/suntong/go-app-demos/pkg => ../pkg
@@ -0,0 +1,49 @@
+// Command 0B2D-codecopy is a paste-bin of code snippets, shared through a form
+// with their language, title and expiry. The server stores them in an embedded
+// bbolt database, the file -db sets (see package snippet), and serves them at
+// short links, /s/{id}, prerendered with their copy button, a view counter and
+// a raw download.
+package main
+
+import (
+	"io/fs"
+
+	"github.com/suntong/go-app-demos/0B2D-codecopy/components"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+// web holds the web directory the resources are served from: the one of the
+// working directory, unless built with -tags embed (see embed.go).
+var web fs.FS
+
+// The main function is the entry point where the app is configured and started.
+// It is executed in 2 different environments: A client (the web browser) and a
+// server.
+func main() {
+	// server.Run associates the components with their paths, on both client
+	// and server-side, and launches the app when executed in the web browser.
+	//
+	// On the server-side, it serves the app with the Handler, an HTTP handler
+	// that serves the client and all its required resources to make it work
+	// into a web browser, along with the snippet API (see store.go).
+	server.Run(server.Config{
+		Routes:    components.Routes,
+		Endpoints: endpoints(),
+		Handler: &app.Handler{
+			Title:  "Snippets",
+			Author: "Suntown Studio",
+			Styles: []string{"/web/styles.css"},
+			Icon: app.Icon{
+				Default:    "/web/copy-icon.png",
+				Large:      "/web/copy-icon.png",
+				AppleTouch: "/web/copy-icon.png",
+			},
+			// The style sheet of the highlighted code blocks.
+			RawHeaders: []string{codeblock.StyleSheet("github")},
+		},
+		Web: web,
+	})
+}
@@ -0,0 +1,83 @@
+//go:build !wasm
+
+package snippet
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/suntong/go-app-demos/pkg/jsonapi"
+)
+
+// Handler serves the API of s under APIPath:
+//
+//	POST APIPath          shares the snippet of a JSON Request, answered as JSON
+//	GET  APIPath+id       answers the snippet id as JSON, counting a view
+//	HEAD APIPath+id       checks the snippet id exists, without counting a view
+//	GET  APIPath+id/raw   downloads the code of the snippet id as text
+func Handler(s *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, APIPath)
+		id, raw := strings.TrimSuffix(p, "/raw"), strings.HasSuffix(p, "/raw")
+		switch {
+		case p == "" && r.Method == http.MethodPost:
+			create(s, w, r)
+		case p == "":
+			w.Header().Set("Allow", http.MethodPost)
+			jsonapi.Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		case r.Method != http.MethodGet && r.Method != http.MethodHead:
+			w.Header().Set("Allow", "GET, HEAD")
+			jsonapi.Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		case !ValidID(id):
+			api.WriteError(w, ErrNotFound)
+		case raw:
+			download(s, w, id)
+		default:
+			view(s, w, r, id)
+		}
+	})
+}
+
+func create(s *Store, w http.ResponseWriter, r *http.Request) {
+	var req Request
+	// The code is at most MaxCode bytes, which its JSON escaping can double.
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*MaxCode+4096))
+	if err := dec.Decode(&req); err != nil {
+		api.WriteError(w, invalid(err.Error()))
+		return
+	}
+	sn, err := s.Create(req)
+	if err != nil {
+		api.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Location", Page(sn.ID))
+	jsonapi.WriteJSON(w, http.StatusCreated, sn)
+}
+
+func view(s *Store, w http.ResponseWriter, r *http.Request, id string) {
+	get := s.View
+	if r.Method == http.MethodHead {
+		get = s.Get
+	}
+	sn, err := get(id)
+	if err != nil {
+		api.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	jsonapi.WriteJSON(w, http.StatusOK, sn)
+}
+
+func download(s *Store, w http.ResponseWriter, id string) {
+	sn, err := s.Get(id)
+	if err != nil {
+		api.WriteError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": sn.Filename()}))
+	w.Write([]byte(sn.Code))
+}
@@ -0,0 +1,73 @@
+//go:build !wasm
+
+package snippet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suntong/go-app-demos/pkg/jsonapi/jsonapitest"
+)
+
+func TestAPI(t *testing.T) {
+	now := time.Now()
+	srv := httptest.NewServer(Handler(openStore(t, &now)))
+	defer srv.Close()
+	c := Client{URL: srv.URL}
+	ctx := context.Background()
+
+	sn, err := c.Create(ctx, Request{Title: "Hello", Language: "JavaScript", Code: "console.log(1)", Lifetime: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := http.Head(srv.URL + APIPath + sn.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Body.Close()
+	if head.StatusCode != http.StatusOK {
+		t.Errorf("head = %d, want %d", head.StatusCode, http.StatusOK)
+	}
+	if got, err := c.Get(ctx, sn.ID); err != nil || got.Code != sn.Code || got.Views != 1 {
+		t.Errorf("get = %+v, %v, want the snippet viewed once", got, err)
+	}
+
+	res, err := http.Get(srv.URL + Raw(sn.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != sn.Code {
+		t.Errorf("raw = %q, want %q", body, sn.Code)
+	}
+	if got, want := res.Header.Get("Content-Disposition"), "attachment; filename="+sn.ID+".js"; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+
+	if _, err := c.Get(ctx, "00000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get unknown: error = %v, want ErrNotFound", err)
+	}
+	_, err = c.Create(ctx, Request{Code: "x", Lifetime: time.Minute})
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "unsupported lifetime 1m0s") {
+		t.Errorf("create invalid: error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	now := time.Now()
+	jsonapitest.Errors(t, Handler(openStore(t, &now)), []jsonapitest.Request{
+		{Method: http.MethodGet, Path: APIPath, Want: http.StatusMethodNotAllowed},
+		{Method: http.MethodDelete, Path: APIPath + "abcd1234", Want: http.StatusMethodNotAllowed},
+		{Method: http.MethodGet, Path: APIPath + "nope", Want: http.StatusNotFound},
+		{Method: http.MethodGet, Path: APIPath + "abcd1234/raw", Want: http.StatusNotFound},
+		{Method: http.MethodPost, Path: APIPath, Body: "{", Want: http.StatusBadRequest},
+		{Method: http.MethodPost, Path: APIPath, Body: `{"code": ""}`, Want: http.StatusBadRequest},
+	})
+}
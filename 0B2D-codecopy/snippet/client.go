@@ -0,0 +1,46 @@
+package snippet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Client calls the API of a server. In the web browser, net/http goes through
+// the fetch API, so Client works the same in the app and in tests.
+type Client struct {
+	// The URL of the server. Defaults to the one of the page, in the web
+	// browser.
+	URL string
+}
+
+// Create shares the snippet of r, and returns it.
+func (c Client) Create(ctx context.Context, r Request) (Snippet, error) {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return Snippet{}, err
+	}
+	return c.do(ctx, http.MethodPost, APIPath, body)
+}
+
+// Get returns the snippet id, counting a view.
+func (c Client) Get(ctx context.Context, id string) (Snippet, error) {
+	if !ValidID(id) {
+		return Snippet{}, ErrNotFound
+	}
+	return c.do(ctx, http.MethodGet, APIPath+id, nil)
+}
+
+// do sends a request with the JSON body to the API path p, and returns the
+// snippet answered.
+func (c Client) do(ctx context.Context, method, p string, body []byte) (Snippet, error) {
+	ctype := ""
+	if body != nil {
+		ctype = "application/json"
+	}
+	var s Snippet
+	if err := api.Do(ctx, c.URL, method, p, ctype, body, &s); err != nil {
+		return Snippet{}, err
+	}
+	return s, nil
+}
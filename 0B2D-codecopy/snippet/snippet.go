@@ -0,0 +1,176 @@
+// Package snippet is the paste-bin behind the 0B2D-codecopy demo: the code
+// snippets shared through its form, the API the server serves them with, and
+// the client the app calls it through.
+//
+// The snippets are kept in an embedded bbolt database, which only exists on
+// the server-side: Store and Handler are not built for the web browser.
+package snippet
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/suntong/go-app-demos/pkg/jsonapi"
+)
+
+const (
+	// APIPath is the path the API is served under: see Handler.
+	APIPath = "/api/snippets/"
+
+	// PageRoute is the route pattern of the snippet pages, for server.Route.
+	PageRoute = `^/s/[0-9A-Za-z]+$`
+
+	// MaxCode is the maximum size of the code of a snippet, in bytes.
+	MaxCode = 64 << 10
+
+	// MaxTitle is the maximum length of the title of a snippet, in
+	// characters.
+	MaxTitle = 100
+
+	// IDLen is the length of the snippet IDs.
+	IDLen = 8
+)
+
+var (
+	// ErrNotFound is returned for snippets that do not exist, or expired.
+	ErrNotFound = errors.New("snippet not found")
+
+	// ErrInvalid is wrapped by the errors of the requests that cannot be
+	// shared, such as one with no code.
+	ErrInvalid = errors.New("invalid snippet")
+)
+
+// api answers the errors of the API, and turns them back into errors in
+// Client.
+var api = jsonapi.API{
+	Name: "snippet API",
+	Statuses: []jsonapi.Status{
+		{Err: ErrNotFound, Code: http.StatusNotFound},
+		{Err: ErrInvalid, Code: http.StatusBadRequest},
+	},
+}
+
+// Snippet is a shared piece of code.
+type Snippet struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Language string `json:"language"`
+	Code     string `json:"code"`
+
+	Created time.Time `json:"created"`
+
+	// When the snippet expires; never when zero.
+	Expires time.Time `json:"expires"`
+
+	// The number of times the snippet was viewed in the app.
+	Views int `json:"views"`
+}
+
+// Expired reports whether s is expired at now.
+func (s Snippet) Expired(now time.Time) bool {
+	return !s.Expires.IsZero() && !now.Before(s.Expires)
+}
+
+// Filename returns the name the code of s is downloaded as: its ID, with the
+// extension of its language, or .txt.
+func (s Snippet) Filename() string {
+	ext := ".txt"
+	if l := lexers.Get(s.Language); l != nil {
+		for _, f := range l.Config().Filenames {
+			// Patterns such as *.go, but not *.[ch] or Makefile.
+			if e := path.Ext(f); strings.HasPrefix(f, "*.") && !strings.ContainsAny(e, "[*?") {
+				ext = e
+				break
+			}
+		}
+	}
+	return s.ID + ext
+}
+
+// Request is a snippet to share.
+type Request struct {
+	Title string `json:"title"`
+
+	// A language known to chroma, the highlighter of package codeblock, or
+	// empty to guess it from the code.
+	Language string `json:"language"`
+
+	Code string `json:"code"`
+
+	// How long the snippet is kept, one of Lifetimes.
+	Lifetime time.Duration `json:"lifetime"`
+}
+
+// Validate returns an error wrapping ErrInvalid when r cannot be shared.
+func (r Request) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Code) == "":
+		return invalid("no code")
+	case len(r.Code) > MaxCode:
+		return invalid("code longer than 64 KiB")
+	case utf8.RuneCountInString(r.Title) > MaxTitle:
+		return invalid("title longer than 100 characters")
+	case r.Language != "" && lexers.Get(r.Language) == nil:
+		return invalid("unknown language " + r.Language)
+	}
+	for _, l := range Lifetimes {
+		if r.Lifetime == l.Duration {
+			return nil
+		}
+	}
+	return invalid("unsupported lifetime " + r.Lifetime.String())
+}
+
+func invalid(reason string) error {
+	return jsonapi.Wrap(ErrInvalid, reason)
+}
+
+// Lifetime is a choice of how long a snippet is kept.
+type Lifetime struct {
+	Label    string
+	Duration time.Duration
+}
+
+// Lifetimes are the lifetimes a snippet can be given. A zero Duration keeps it
+// forever.
+var Lifetimes = []Lifetime{
+	{"1 hour", time.Hour},
+	{"1 day", 24 * time.Hour},
+	{"1 week", 7 * 24 * time.Hour},
+	{"Never", 0},
+}
+
+// Languages are the languages offered by the form, after the guess from the
+// code.
+var Languages = []string{
+	"Go", "JavaScript", "TypeScript", "Python", "Rust", "Java", "C", "C++",
+	"Bash", "HTML", "CSS", "JSON", "YAML", "SQL", "Markdown", "plaintext",
+}
+
+var idPattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// ValidID reports whether id has the form of a snippet ID.
+func ValidID(id string) bool {
+	return len(id) == IDLen && idPattern.MatchString(id)
+}
+
+// Page returns the path of the page of the snippet id.
+func Page(id string) string {
+	return "/s/" + id
+}
+
+// PageID returns the ID of the snippet of the page at path p.
+func PageID(p string) string {
+	return strings.TrimPrefix(p, "/s/")
+}
+
+// Raw returns the path the code of the snippet id is downloaded from.
+func Raw(id string) string {
+	return APIPath + id + "/raw"
+}
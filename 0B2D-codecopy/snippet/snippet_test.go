@@ -0,0 +1,72 @@
+package snippet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Request
+		ok   bool
+	}{
+		{"guessed language", Request{Code: "x := 1"}, true},
+		{"language", Request{Title: "Hello", Language: "go", Code: "x := 1", Lifetime: time.Hour}, true},
+		{"no code", Request{Code: " \n"}, false},
+		{"long code", Request{Code: strings.Repeat("x", MaxCode+1)}, false},
+		{"long title", Request{Title: strings.Repeat("é", MaxTitle+1), Code: "x"}, false},
+		{"unknown language", Request{Language: "nolang", Code: "x"}, false},
+		{"unsupported lifetime", Request{Code: "x", Lifetime: time.Minute}, false},
+	}
+	for _, test := range tests {
+		err := test.r.Validate()
+		if test.ok && err != nil {
+			t.Errorf("%s: error = %v", test.name, err)
+		}
+		if !test.ok && !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: error = %v, want ErrInvalid", test.name, err)
+		}
+	}
+}
+
+func TestFilename(t *testing.T) {
+	for language, want := range map[string]string{
+		"Go":         "abcd1234.go",
+		"JavaScript": "abcd1234.js",
+		"C":          "abcd1234.c",
+		"":           "abcd1234.txt",
+		"nolang":     "abcd1234.txt",
+	} {
+		if got := (Snippet{ID: "abcd1234", Language: language}).Filename(); got != want {
+			t.Errorf("filename of %q = %q, want %q", language, got, want)
+		}
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Now()
+	if (Snippet{}).Expired(now) {
+		t.Error("snippet with no expiry expired")
+	}
+	if !(Snippet{Expires: now}).Expired(now) {
+		t.Error("snippet not expired at its expiry")
+	}
+	if (Snippet{Expires: now.Add(time.Second)}).Expired(now) {
+		t.Error("snippet expired before its expiry")
+	}
+}
+
+func TestPaths(t *testing.T) {
+	if !ValidID("aB3dE6gH") || ValidID("aB3dE6g") || ValidID("aB3dE6g/") {
+		t.Error("ValidID does not check the ID length and characters")
+	}
+	if got := PageID(Page("aB3dE6gH")); got != "aB3dE6gH" {
+		t.Errorf("PageID(Page(id)) = %q", got)
+	}
+	if got, want := Raw("aB3dE6gH"), "/api/snippets/aB3dE6gH/raw"; got != want {
+		t.Errorf("Raw = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+//go:build !wasm
+
+package snippet
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucket = []byte("snippets")
+
+// Store keeps the snippets in a bbolt database file. It is safe for
+// concurrent use, but the file can only be opened by one process at a time.
+type Store struct {
+	db *bolt.DB
+
+	// now returns the current time, which the tests set.
+	now func() time.Time
+}
+
+// Open opens the store in the database file path, created if it does not
+// exist, and deletes the snippets that expired.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &Store{db: db, now: time.Now}
+	if _, err := s.Purge(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Create stores a snippet for r under a new random ID, and returns it. When
+// r has no language, the one guessed from the code is stored.
+func (s *Store) Create(r Request) (Snippet, error) {
+	if err := r.Validate(); err != nil {
+		return Snippet{}, err
+	}
+	sn := Snippet{
+		Title:    r.Title,
+		Language: r.Language,
+		Code:     r.Code,
+		Created:  s.now().UTC().Truncate(time.Second),
+	}
+	if sn.Language == "" {
+		sn.Language, _ = codeblock.Lines(r.Code, "")
+	}
+	if r.Lifetime != 0 {
+		sn.Expires = sn.Created.Add(r.Lifetime)
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		for {
+			id, err := newID()
+			if err != nil {
+				return err
+			}
+			if b.Get([]byte(id)) == nil {
+				sn.ID = id
+				return put(b, sn)
+			}
+		}
+	})
+	return sn, err
+}
+
+// Get returns the snippet id, without counting a view, as for the
+// prerendering of its page.
+func (s *Store) Get(id string) (Snippet, error) {
+	var sn Snippet
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		sn, err = get(tx.Bucket(bucket), id)
+		return err
+	})
+	if err == nil && sn.Expired(s.now()) {
+		return Snippet{}, ErrNotFound
+	}
+	return sn, err
+}
+
+// View returns the snippet id after counting a view of it. It deletes the
+// snippet when expired.
+func (s *Store) View(id string) (Snippet, error) {
+	var sn Snippet
+	expired := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		var err error
+		if sn, err = get(b, id); err != nil {
+			return err
+		}
+		// Failing with ErrNotFound would roll the deletion back.
+		if expired = sn.Expired(s.now()); expired {
+			return b.Delete([]byte(id))
+		}
+		sn.Views++
+		return put(b, sn)
+	})
+	switch {
+	case err != nil:
+		return Snippet{}, err
+	case expired:
+		return Snippet{}, ErrNotFound
+	}
+	return sn, nil
+}
+
+// Purge deletes the snippets that expired, and returns how many there were.
+func (s *Store) Purge() (int, error) {
+	now := s.now()
+	n := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var expired [][]byte
+		err := tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+			var sn Snippet
+			if err := json.Unmarshal(v, &sn); err != nil {
+				return err
+			}
+			if sn.Expired(now) {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Keys cannot be deleted while iterating over them.
+		for _, k := range expired {
+			if err := tx.Bucket(bucket).Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(expired)
+		return nil
+	})
+	return n, err
+}
+
+func get(b *bolt.Bucket, id string) (Snippet, error) {
+	v := b.Get([]byte(id))
+	if v == nil {
+		return Snippet{}, ErrNotFound
+	}
+	var sn Snippet
+	err := json.Unmarshal(v, &sn)
+	return sn, err
+}
+
+func put(b *bolt.Bucket, sn Snippet) error {
+	v, err := json.Marshal(sn)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(sn.ID), v)
+}
+
+const idChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// newID returns a random ID of IDLen characters.
+func newID() (string, error) {
+	id := make([]byte, IDLen)
+	max := big.NewInt(int64(len(idChars)))
+	for i := range id {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.New("snippet ID: " + err.Error())
+		}
+		id[i] = idChars[n.Int64()]
+	}
+	return string(id), nil
+}
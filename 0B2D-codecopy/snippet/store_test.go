@@ -0,0 +1,124 @@
+//go:build !wasm
+
+package snippet
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openStore opens a store in a temporary directory, at the time *now.
+func openStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "snippets.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return *now }
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := openStore(t, &now)
+
+	sn, err := s.Create(Request{Title: "Hello", Language: "go", Code: "fmt.Println(1)", Lifetime: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ValidID(sn.ID) || sn.Title != "Hello" || !sn.Created.Equal(now) || !sn.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("created snippet = %+v", sn)
+	}
+
+	for i := 1; i <= 2; i++ {
+		got, err := s.View(sn.ID)
+		if err != nil || got.Views != i {
+			t.Errorf("view %d: %+v, %v", i, got, err)
+		}
+	}
+	if got, err := s.Get(sn.ID); err != nil || got.Views != 2 || got.Code != sn.Code {
+		t.Errorf("get = %+v, %v, want 2 views", got, err)
+	}
+
+	if _, err := s.Get("00000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get unknown: error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Create(Request{}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("create empty: error = %v, want ErrInvalid", err)
+	}
+}
+
+func TestStoreGuessesLanguage(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	sn, err := s.Create(Request{Code: "#!/bin/bash\necho hello\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sn.Language != "Bash" {
+		t.Errorf("language = %q, want Bash", sn.Language)
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	hour, err := s.Create(Request{Code: "x", Lifetime: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day, err := s.Create(Request{Code: "y", Lifetime: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	forever, err := s.Create(Request{Code: "z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := s.Get(hour.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get expired: error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.View(hour.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("view expired: error = %v, want ErrNotFound", err)
+	}
+
+	now = now.Add(48 * time.Hour)
+	if n, err := s.Purge(); err != nil || n != 1 {
+		t.Errorf("purge = %d, %v, want the day snippet purged, the hour one deleted by the view", n, err)
+	}
+	if _, err := s.Get(day.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get purged: error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Get(forever.ID); err != nil {
+		t.Errorf("get unexpiring: error = %v", err)
+	}
+}
+
+func TestStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snippets.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sn, err := s.Create(Request{Code: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got, err := s.Get(sn.ID); err != nil || got.Code != "x" {
+		t.Errorf("get after reopening = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !wasm
+
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/suntong/go-app-demos/0B2D-codecopy/components"
+	"github.com/suntong/go-app-demos/0B2D-codecopy/snippet"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+var dbFlag = flag.String("db", "snippets.db", "database file the snippets are stored in")
+
+// endpoints opens the snippet store, which the snippet pages are prerendered
+// from, and returns the API serving it. The flags are parsed first, for the
+// -db one.
+func endpoints() []server.Endpoint {
+	flag.Parse()
+	s, err := snippet.Open(*dbFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	components.Snippets = s
+	return []server.Endpoint{
+		{Pattern: snippet.APIPath, Handler: snippet.Handler(s)},
+	}
+}
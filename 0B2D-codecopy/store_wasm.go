@@ -0,0 +1,8 @@
+package main
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// The snippet store only exists on the server-side.
+func endpoints() []server.Endpoint {
+	return nil
+}
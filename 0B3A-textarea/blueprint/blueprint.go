@@ -0,0 +1,201 @@
+// Package blueprint builds Factorio blueprints from black and white images,
+// and encodes them as the blueprint strings the game imports: a version
+// byte, "0", followed by the base64 of the zlib-deflated JSON of the
+// blueprint.
+package blueprint
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Version is the version of the game the blueprints are made for, 1.1.110,
+// as encoded by the game: 16 bits for each of the major, minor, patch and
+// build numbers.
+const Version uint64 = 1<<48 | 1<<32 | 110<<16
+
+// formatVersion is the version byte of the blueprint strings.
+const formatVersion = '0'
+
+// ErrFormat tells a string is not a blueprint string.
+var ErrFormat = errors.New("blueprint: invalid blueprint string")
+
+// Container is the JSON object encoded in the blueprint strings.
+type Container struct {
+	Blueprint *Blueprint `json:"blueprint,omitempty"`
+}
+
+// Blueprint is a Factorio blueprint. Only the fields of the blueprints made
+// of tiles and 1x1 entities are supported.
+type Blueprint struct {
+	Item        string   `json:"item"`
+	Label       string   `json:"label,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Icons       []Icon   `json:"icons,omitempty"`
+	Entities    []Entity `json:"entities,omitempty"`
+	Tiles       []Tile   `json:"tiles,omitempty"`
+	Version     uint64   `json:"version"`
+}
+
+// Icon is an icon of a blueprint, Index being 1 to 4.
+type Icon struct {
+	Signal Signal `json:"signal"`
+	Index  int    `json:"index"`
+}
+
+// Signal names an item, such as {Type: "item", Name: "stone-wall"}.
+type Signal struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Entity is an entity of a blueprint. Numbers start at 1.
+type Entity struct {
+	Number   int      `json:"entity_number"`
+	Name     string   `json:"name"`
+	Position Position `json:"position"`
+}
+
+// Tile is a tile of a blueprint.
+type Tile struct {
+	Name     string   `json:"name"`
+	Position Position `json:"position"`
+}
+
+// Position is the position of an entity or a tile. The position of a 1x1
+// entity is the center of its tile, such as {0.5, 0.5}.
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Encode returns the blueprint string of c.
+func Encode(c Container) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteByte(formatVersion)
+	enc := base64.NewEncoder(base64.StdEncoding, &b)
+	z, err := zlib.NewWriterLevel(enc, zlib.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := z.Write(data); err != nil {
+		return "", err
+	}
+	if err := z.Close(); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Decode returns the container encoded in the blueprint string s. The white
+// space around s, as pasted, is ignored.
+func Decode(s string) (Container, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s[0] != formatVersion {
+		return Container{}, ErrFormat
+	}
+	data, err := base64.StdEncoding.DecodeString(s[1:])
+	if err != nil {
+		return Container{}, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	z, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return Container{}, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	defer z.Close()
+	js, err := io.ReadAll(z)
+	if err != nil {
+		return Container{}, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	var c Container
+	if err := json.Unmarshal(js, &c); err != nil {
+		return Container{}, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	return c, nil
+}
+
+// Kind is the kind of item placed for a pixel: a tile, or a 1x1 entity.
+type Kind struct {
+	Name string
+
+	// Tile tells the item is a tile, rather than an entity.
+	Tile bool
+}
+
+// Kinds are the items an image can be made of.
+var Kinds = []Kind{
+	{Name: "stone-path", Tile: true},
+	{Name: "concrete", Tile: true},
+	{Name: "refined-concrete", Tile: true},
+	{Name: "landfill", Tile: true},
+	{Name: "stone-wall"},
+	{Name: "small-lamp"},
+}
+
+// Scales are the numbers of pixels an item can stand for, horizontally and
+// vertically.
+var Scales = []int{1, 2, 4, 8}
+
+// FromImage returns the blueprint placing an item of kind k for each scale
+// by scale block of img whose pixels are at least half black. The blocks are those
+// of the image, top left first, and a block at its right or bottom edge
+// may be smaller.
+func FromImage(img image.Image, k Kind, scale int, label string) *Blueprint {
+	if scale < 1 {
+		scale = 1
+	}
+	b := &Blueprint{
+		Item:    "blueprint",
+		Label:   label,
+		Icons:   []Icon{{Signal: Signal{Type: "item", Name: k.Name}, Index: 1}},
+		Version: Version,
+	}
+	bounds := img.Bounds()
+	for y := 0; bounds.Min.Y+y*scale < bounds.Max.Y; y++ {
+		for x := 0; bounds.Min.X+x*scale < bounds.Max.X; x++ {
+			block := image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale).
+				Add(bounds.Min).Intersect(bounds)
+			if !dark(img, block) {
+				continue
+			}
+			if k.Tile {
+				b.Tiles = append(b.Tiles, Tile{Name: k.Name, Position: Position{float64(x), float64(y)}})
+			} else {
+				b.Entities = append(b.Entities, Entity{
+					Number:   len(b.Entities) + 1,
+					Name:     k.Name,
+					Position: Position{float64(x) + 0.5, float64(y) + 0.5},
+				})
+			}
+		}
+	}
+	return b
+}
+
+// dark tells whether at least half the pixels of the block r of img are
+// black, or rather darker than mid-gray.
+func dark(img image.Image, r image.Rectangle) bool {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if c, _, _, _ := img.At(x, y).RGBA(); c < 0x8000 {
+				n++
+			}
+		}
+	}
+	return 2*n >= r.Dx()*r.Dy()
+}
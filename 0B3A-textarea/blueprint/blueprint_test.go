@@ -0,0 +1,143 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// The samples of testdata are blueprint strings encoded by Python's zlib
+// module, as the game does, along with their JSON.
+var samples = []string{"walls", "tiles"}
+
+func TestDecode(t *testing.T) {
+	for _, name := range samples {
+		s, err := os.ReadFile("testdata/" + name + ".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		js, err := os.ReadFile("testdata/" + name + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want Container
+		if err := json.Unmarshal(js, &want); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Decode(string(s))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decoded %+v, want %+v", name, got.Blueprint, want.Blueprint)
+		}
+
+		// Encoded again, the blueprint decodes the same.
+		encoded, err := Encode(got)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		again, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("%s: decoding %q: %v", name, encoded, err)
+		}
+		if !reflect.DeepEqual(again, want) {
+			t.Errorf("%s: round trip %+v, want %+v", name, again.Blueprint, want.Blueprint)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"1eNqrVkrOz0nRsKwEAKqpAx4=",
+		"0 not base64",
+		"0bm90IHpsaWI=",
+		"0eNqrVkpUAgADOgEh",
+	} {
+		if _, err := Decode(s); !errors.Is(err, ErrFormat) {
+			t.Errorf("Decode(%q): error = %v, want %v", s, err, ErrFormat)
+		}
+	}
+}
+
+// letterL returns a 4x4 image, white but for an L of black pixels:
+//
+//	X...
+//	X...
+//	XX..
+//	....
+func letterL() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, p := range []image.Point{{0, 0}, {0, 1}, {0, 2}, {1, 2}} {
+		img.SetGray(p.X, p.Y, color.Gray{})
+	}
+	return img
+}
+
+func TestFromImage(t *testing.T) {
+	b := FromImage(letterL(), Kind{Name: "stone-wall"}, 1, "L")
+	want := []Entity{
+		{1, "stone-wall", Position{0.5, 0.5}},
+		{2, "stone-wall", Position{0.5, 1.5}},
+		{3, "stone-wall", Position{0.5, 2.5}},
+		{4, "stone-wall", Position{1.5, 2.5}},
+	}
+	if !reflect.DeepEqual(b.Entities, want) || b.Tiles != nil {
+		t.Errorf("entities = %v, tiles = %v, want %v", b.Entities, b.Tiles, want)
+	}
+	if b.Label != "L" || b.Version != Version || b.Icons[0].Signal.Name != "stone-wall" {
+		t.Errorf("blueprint = %+v", b)
+	}
+
+	// At scale 2, the blocks on the left are half black, those on the right
+	// white, but for one pixel.
+	b = FromImage(letterL(), Kind{Name: "concrete", Tile: true}, 2, "")
+	if want := []Tile{{"concrete", Position{0, 0}}, {"concrete", Position{0, 1}}}; !reflect.DeepEqual(b.Tiles, want) || b.Entities != nil {
+		t.Errorf("tiles = %v, entities = %v, want %v", b.Tiles, b.Entities, want)
+	}
+
+	// The blueprint strings of the images decode to their blueprint.
+	b = FromImage(letterL(), Kinds[0], 1, "L")
+	s, err := Encode(Container{Blueprint: b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Blueprint, b) {
+		t.Errorf("decoded %+v, want %+v", c.Blueprint, b)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	b := FromImage(letterL(), Kind{Name: "small-lamp"}, 1, "")
+	img := Preview(b, 4, 4, 5)
+	if got := img.Bounds(); got != image.Rect(0, 0, 20, 20) {
+		t.Fatalf("preview bounds = %v", got)
+	}
+	for _, test := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{2, 2, colors["small-lamp"]},
+		{7, 12, colors["small-lamp"]},
+		{7, 7, ground},
+		{5, 7, gridLine},
+		{0, 0, gridLine},
+	} {
+		if got := img.RGBAAt(test.x, test.y); got != test.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
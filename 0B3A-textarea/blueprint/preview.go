@@ -0,0 +1,62 @@
+package blueprint
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// colors are those of the items in the previews, as seen in the game.
+var colors = map[string]color.RGBA{
+	"stone-path":       {0x6e, 0x68, 0x5c, 0xff},
+	"concrete":         {0x8c, 0x8c, 0x88, 0xff},
+	"refined-concrete": {0xa8, 0xa8, 0xa4, 0xff},
+	"landfill":         {0x7a, 0x5c, 0x3a, 0xff},
+	"stone-wall":       {0xd8, 0xd4, 0xc8, 0xff},
+	"small-lamp":       {0xff, 0xe0, 0x5a, 0xff},
+}
+
+var (
+	ground   = color.RGBA{0x24, 0x24, 0x24, 0xff}
+	gridLine = color.RGBA{0x44, 0x44, 0x44, 0xff}
+	unknown  = color.RGBA{0xff, 0x00, 0xff, 0xff}
+)
+
+// Preview returns an image of the w by h tiles of b from the origin, with
+// cell by cell pixels per tile. The grid of the tiles is drawn when the cells
+// are large enough to show it.
+func Preview(b *Blueprint, w, h, cell int) *image.RGBA {
+	if cell < 1 {
+		cell = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w*cell, h*cell))
+	draw.Draw(img, img.Bounds(), &image.Uniform{ground}, image.Point{}, draw.Src)
+
+	grid := cell >= 4
+	if grid {
+		for x := 0; x < w*cell; x += cell {
+			draw.Draw(img, image.Rect(x, 0, x+1, h*cell), &image.Uniform{gridLine}, image.Point{}, draw.Src)
+		}
+		for y := 0; y < h*cell; y += cell {
+			draw.Draw(img, image.Rect(0, y, w*cell, y+1), &image.Uniform{gridLine}, image.Point{}, draw.Src)
+		}
+	}
+	fill := func(name string, x, y int) {
+		c, ok := colors[name]
+		if !ok {
+			c = unknown
+		}
+		r := image.Rect(x*cell, y*cell, (x+1)*cell, (y+1)*cell)
+		if grid {
+			r.Min = r.Min.Add(image.Pt(1, 1))
+		}
+		draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+	for _, t := range b.Tiles {
+		fill(t.Name, int(t.Position.X), int(t.Position.Y))
+	}
+	for _, e := range b.Entities {
+		fill(e.Name, int(e.Position.X), int(e.Position.Y))
+	}
+	return img
+}
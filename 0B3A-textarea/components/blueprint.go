@@ -0,0 +1,110 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/mlctrez/imgtofactbp/conversions"
+	"github.com/suntong/go-app-demos/0B3A-textarea/blueprint"
+	pkgclipboard "github.com/suntong/go-app-demos/pkg/clipboard"
+)
+
+// defaultScale is the index in blueprint.Scales of the scale before one is
+// picked, 4 pixels per item.
+const defaultScale = 2
+
+// blueprintControls renders the settings of the blueprint of the
+// thresholded image, and the button copying its blueprint string.
+func (uc *appControl) blueprintControls() app.UI {
+	return app.P().Body(
+		app.Label().Body(
+			app.Text("Blueprint of "),
+			app.Select().ID("kind").OnChange(uc.onKind).Body(
+				app.Range(blueprint.Kinds).Slice(func(i int) app.UI {
+					return option(i, blueprint.Kinds[i].Name, uc.kind)
+				}),
+			),
+		),
+		app.Label().Body(
+			app.Text(" one per "),
+			app.Select().ID("scale").OnChange(uc.onScale).Body(
+				app.Range(blueprint.Scales).Slice(func(i int) app.UI {
+					s := blueprint.Scales[i]
+					return option(i, fmt.Sprintf("%dx%d", s, s), uc.scale)
+				}),
+			),
+			app.Text(" pixels "),
+		),
+		app.Button().ID("copyBlueprint").Text("Copy blueprint").OnClick(uc.onCopyBlueprint),
+		app.If(uc.copyStatus != "",
+			app.Text(" "+uc.copyStatus),
+		),
+		app.If(uc.blueprint != "",
+			app.Br(),
+			app.Textarea().ID("blueprintString").Cols(80).Rows(4).ReadOnly(true).Text(uc.blueprint),
+		),
+	)
+}
+
+// option renders the option i of a select, selected when i is picked.
+func option(i int, text string, picked int) app.UI {
+	o := app.Option().Value(i).Text(text)
+	if i == picked {
+		o = o.Selected(true)
+	}
+	return o
+}
+
+func (uc *appControl) onKind(ctx app.Context, e app.Event) {
+	uc.kind, _ = strconv.Atoi(ctx.JSSrc().Get("value").String())
+	uc.renderBlueprint()
+}
+
+func (uc *appControl) onScale(ctx app.Context, e app.Event) {
+	uc.scale, _ = strconv.Atoi(ctx.JSSrc().Get("value").String())
+	uc.renderBlueprint()
+}
+
+// newBlueprint returns the blueprint of the thresholded image, with the item
+// and the scale picked.
+func (uc *appControl) newBlueprint() *blueprint.Blueprint {
+	return blueprint.FromImage(uc.bw, blueprint.Kinds[uc.kind], blueprint.Scales[uc.scale], "Pasted image")
+}
+
+// renderBlueprint sets the preview of the blueprint, the stage following the
+// thresholded image, as wide as the other stages.
+func (uc *appControl) renderBlueprint() {
+	scale := blueprint.Scales[uc.scale]
+	b := uc.bw.Bounds()
+	w := (b.Dx() + scale - 1) / scale
+	h := (b.Dy() + scale - 1) / scale
+	cell := ImageRenderWidth / w
+	preview := blueprint.Preview(uc.newBlueprint(), w, h, cell)
+	uc.setStage(3, stage{ID: "blueprintPreview", Name: "Blueprint", Src: conversions.ImageToBase64(preview)})
+
+	// The string copied is not the one of the new blueprint anymore.
+	uc.blueprint, uc.copyStatus = "", ""
+}
+
+func (uc *appControl) onCopyBlueprint(ctx app.Context, e app.Event) {
+	s, err := blueprint.Encode(blueprint.Container{Blueprint: uc.newBlueprint()})
+	if err != nil {
+		log.Println(err)
+		uc.copyStatus = err.Error()
+		return
+	}
+	uc.blueprint, uc.copyStatus = s, ""
+	ctx.Async(func() {
+		status := fmt.Sprintf("Copied, %d characters", len(s))
+		if err := pkgclipboard.WriteText(ctx, s); err != nil {
+			status = "Copy failed: " + err.Error()
+		}
+		ctx.Dispatch(func(ctx app.Context) {
+			if uc.blueprint == s {
+				uc.copyStatus = status
+			}
+		})
+	})
+}
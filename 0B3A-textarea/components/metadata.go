@@ -0,0 +1,137 @@
+package components
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B3A-textarea/metadata"
+)
+
+// metadataPanel renders the metadata of the pasted image: its format, its
+// camera and location, all its fields and the button downloading it without
+// them, along with the errors reading, decoding or parsing it. It renders
+// nothing before an image is pasted.
+func (uc *appControl) metadataPanel() app.UI {
+	info := uc.info
+	if info == nil {
+		if len(uc.pasteErrors) == 0 {
+			return nil
+		}
+		return app.Section().ID("metadata").Body(
+			app.H3().Text("Metadata"),
+			pasteErrors(uc.pasteErrors),
+		)
+	}
+	return app.Section().ID("metadata").Body(
+		app.H3().Text("Metadata"),
+		app.If(len(uc.pasteErrors) > 0,
+			pasteErrors(uc.pasteErrors),
+		),
+		app.P().Text(fmt.Sprintf("%s, %dx%d, %s, %d bytes",
+			strings.ToUpper(info.Format), info.Width, info.Height, info.ColorModel, len(uc.pasted))),
+		app.If(len(info.Camera) > 0,
+			fieldsTable("Camera", info.Camera, false),
+		),
+		locationLine(info.Location),
+		app.If(len(info.Warnings) > 0,
+			app.Ul().Body(
+				app.Range(info.Warnings).Slice(func(i int) app.UI {
+					return app.Li().Text("Warning: " + info.Warnings[i])
+				}),
+			),
+		),
+		app.If(len(info.Fields) > 0,
+			fieldsTable("All fields", info.Fields, true),
+		).Else(
+			app.P().Text("No metadata"),
+		),
+		app.P().Body(
+			app.Button().ID("stripMetadata").Text("Strip metadata and download").OnClick(uc.onStrip),
+			app.If(uc.stripStatus != "",
+				app.Text(" "+uc.stripStatus),
+			),
+		),
+	)
+}
+
+// pasteErrors renders the errors of the pasted image.
+func pasteErrors(errs []string) app.UI {
+	return app.Ul().ID("pasteErrors").Style("color", "red").Body(
+		app.Range(errs).Slice(func(i int) app.UI {
+			return app.Li().Text(errs[i])
+		}),
+	)
+}
+
+// fieldsTable renders fields in a table, with their group if withGroup.
+func fieldsTable(caption string, fields []metadata.Field, withGroup bool) app.UI {
+	return app.Table().Body(
+		app.Caption().Text(caption),
+		app.TBody().Body(
+			app.Range(fields).Slice(func(i int) app.UI {
+				f := fields[i]
+				return app.Tr().Body(
+					app.If(withGroup,
+						app.Td().Text(f.Group),
+					),
+					app.Th().Style("text-align", "left").Text(f.Name),
+					app.Td().Text(f.Value),
+				)
+			}),
+		),
+	)
+}
+
+// locationLine renders the GPS location l, linked to its map, or nothing
+// without a location.
+func locationLine(l *metadata.Location) app.UI {
+	if l == nil {
+		return nil
+	}
+	return app.P().ID("location").Body(
+		app.Text("GPS location "),
+		app.A().Href(l.MapURL()).Target("_blank").Rel("noopener").Text(l.String()),
+	)
+}
+
+// strippedName returns the name the stripped image of the MIME type is
+// downloaded as.
+func strippedName(mime string) string {
+	ext := strings.TrimPrefix(mime, "image/")
+	if ext == "jpeg" {
+		ext = "jpg"
+	}
+	return "pasted-stripped." + ext
+}
+
+func (uc *appControl) onStrip(ctx app.Context, e app.Event) {
+	pasted := uc.pasted
+	uc.stripStatus = "Stripping…"
+	// Decoding and encoding a photo again takes a while.
+	ctx.Async(func() {
+		b, mime, err := metadata.Strip(pasted)
+		ctx.Dispatch(func(ctx app.Context) {
+			if err != nil {
+				log.Println(err)
+				uc.stripStatus = "Strip failed: " + err.Error()
+				return
+			}
+			download(strippedName(mime), "data:"+mime+";base64,"+base64.StdEncoding.EncodeToString(b))
+			uc.stripStatus = fmt.Sprintf("Downloaded, %d bytes", len(b))
+		})
+	})
+}
+
+// download has the browser download the data URL src as name.
+func download(name, src string) {
+	doc := app.Window().Get("document")
+	a := doc.Call("createElement", "a")
+	a.Set("href", src)
+	a.Set("download", name)
+	doc.Get("body").Call("appendChild", a)
+	a.Call("click")
+	a.Call("remove")
+}
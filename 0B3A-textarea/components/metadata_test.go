@@ -0,0 +1,76 @@
+package components
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B3A-textarea/metadata"
+)
+
+func TestMetadataPanel(t *testing.T) {
+	uc := &appControl{}
+	if ui := uc.metadataPanel(); ui != nil {
+		t.Errorf("metadata panel before a paste: %s", app.HTMLString(ui))
+	}
+
+	pasted, err := os.ReadFile("../metadata/testdata/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.info, err = metadata.Parse(pasted); err != nil {
+		t.Fatal(err)
+	}
+	uc.pasted = pasted
+	html := app.HTMLString(uc.metadataPanel())
+	for _, want := range []string{
+		"JPEG, 32x16, YCbCr",
+		"Gopher Optics",
+		"f/2.8",
+		`id="location"`,
+		"48.858200, 2.294500, 35.0 m",
+		"openstreetmap.org",
+		"Rotate 90 CW",
+		`id="stripMetadata"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("metadata panel without %q: %s", want, html)
+		}
+	}
+}
+
+func TestMetadataPanelErrors(t *testing.T) {
+	uc := &appControl{pasteErrors: []string{"Decoding the pasted image failed: image: unknown format"}}
+	html := app.HTMLString(uc.metadataPanel())
+	if !strings.Contains(html, `id="pasteErrors"`) || !strings.Contains(html, uc.pasteErrors[0]) {
+		t.Errorf("metadata panel without %q: %s", uc.pasteErrors[0], html)
+	}
+
+	// Cut, the image does not decode, but its metadata still parse.
+	pasted, err := os.ReadFile("../metadata/testdata/photo.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.info, err = metadata.Parse(pasted[:len(pasted)-16]); err != nil {
+		t.Fatal(err)
+	}
+	html = app.HTMLString(uc.metadataPanel())
+	for _, want := range []string{uc.pasteErrors[0], "Gopher Optics"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("metadata panel without %q: %s", want, html)
+		}
+	}
+}
+
+func TestStrippedName(t *testing.T) {
+	for mime, want := range map[string]string{
+		"image/jpeg": "pasted-stripped.jpg",
+		"image/png":  "pasted-stripped.png",
+		"image/gif":  "pasted-stripped.gif",
+	} {
+		if got := strippedName(mime); got != want {
+			t.Errorf("strippedName(%q) = %q, want %q", mime, got, want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package components
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/mlctrez/imgtofactbp/conversions"
+)
+
+const (
+	// maxThreshold is the largest threshold, that of the white pixels, as
+	// the color channels are 16-bit.
+	maxThreshold = 0xffff
+
+	// defaultThreshold is the threshold before the slider is moved.
+	defaultThreshold = maxThreshold / 2
+)
+
+// stage is a step of the processing of the pasted image, shown in the images
+// row of the page.
+type stage struct {
+	// ID is the one of the img element of the stage.
+	ID string
+
+	Name string
+
+	// Src is the data URL of the PNG image.
+	Src string
+}
+
+// Filename returns the name the image of the stage is downloaded as.
+func (s stage) Filename() string {
+	return s.ID + ".png"
+}
+
+// prepare returns original resized to ImageRenderWidth, and its grayscale
+// version, the images the threshold applies to.
+func prepare(original image.Image) (scaled, gray image.Image, err error) {
+	scaled = conversions.ResizeWidth(original, ImageRenderWidth)
+	if gray, err = conversions.GrayScale(scaled); err != nil {
+		return nil, nil, err
+	}
+	return scaled, gray, nil
+}
+
+// threshold returns the black and white version of gray: the pixels lighter
+// than t are white, and the others black, or the other way around when
+// inverted.
+func threshold(gray image.Image, t uint32, inverted bool) *image.Gray {
+	on, off := color.White, color.Black
+	if inverted {
+		on, off = off, on
+	}
+	b := gray.Bounds()
+	img := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if r, _, _, _ := gray.At(x, y).RGBA(); r > t {
+				img.Set(x, y, on)
+			} else {
+				img.Set(x, y, off)
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,87 @@
+package components
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+// gradient returns an image whose columns go from black to white.
+func gradient(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / (w - 1))
+			img.Set(x, y, color.RGBA{R: v, G: v, B: v / 2, A: 0xff})
+		}
+	}
+	return img
+}
+
+func TestPrepare(t *testing.T) {
+	scaled, gray, err := prepare(gradient(600, 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, img := range map[string]image.Image{"scaled": scaled, "gray": gray} {
+		if b := img.Bounds(); b.Dx() != ImageRenderWidth || b.Dy() != 100 {
+			t.Errorf("%s image is %dx%d, want %dx100", name, b.Dx(), b.Dy(), ImageRenderWidth)
+		}
+	}
+	if r, g, b, _ := gray.At(ImageRenderWidth/2, 50).RGBA(); r != g || g != b {
+		t.Errorf("gray pixel = %d, %d, %d, not gray", r, g, b)
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	uc := &appControl{}
+	if ui := uc.statusLine(); ui != nil {
+		t.Errorf("status line without a failure: %s", app.HTMLString(ui))
+	}
+	uc.pasteStatus = "Processing the pasted image failed: unsupported"
+	if html := app.HTMLString(uc.statusLine()); !strings.Contains(html, `id="pasteStatus"`) || !strings.Contains(html, uc.pasteStatus) {
+		t.Errorf("status line = %s, want %q", html, uc.pasteStatus)
+	}
+}
+
+func TestUploadLine(t *testing.T) {
+	uc := &appControl{}
+	if html := app.HTMLString(uc.uploadLine()); !strings.Contains(html, `href="gallery"`) || strings.Contains(html, "<progress") {
+		t.Errorf("upload line before a paste = %s, want the link only", html)
+	}
+	uc.upload = &upload.Progress{Sent: 1, Total: 4}
+	if html := app.HTMLString(uc.uploadLine()); !strings.Contains(html, "<progress") {
+		t.Errorf("upload line = %s, want the progress", html)
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 3, 1))
+	gray.SetGray(0, 0, color.Gray{Y: 0x10})
+	gray.SetGray(1, 0, color.Gray{Y: 0x80})
+	gray.SetGray(2, 0, color.Gray{Y: 0xf0})
+
+	tests := []struct {
+		t        uint32
+		inverted bool
+		want     []uint8
+	}{
+		{defaultThreshold, false, []uint8{0, 0xff, 0xff}},
+		{defaultThreshold, true, []uint8{0xff, 0, 0}},
+		{0, false, []uint8{0xff, 0xff, 0xff}},
+		{maxThreshold, false, []uint8{0, 0, 0}},
+		{0xc000, false, []uint8{0, 0, 0xff}},
+	}
+	for _, test := range tests {
+		img := threshold(gray, test.t, test.inverted)
+		for x, want := range test.want {
+			if got := img.GrayAt(x, 0).Y; got != want {
+				t.Errorf("threshold %#x, inverted %t: pixel %d = %#x, want %#x", test.t, test.inverted, x, got, want)
+			}
+		}
+	}
+}
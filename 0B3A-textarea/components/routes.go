@@ -0,0 +1,12 @@
+package components
+
+import (
+	"github.com/suntong/go-app-demos/pkg/server"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &appControl{}},
+	{Path: upload.GalleryRoute, Compo: &upload.Gallery{}},
+}
@@ -0,0 +1,14 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{Path: "/", Contains: []string{`placeholder="Paste your text"`, `id="uploadedImage"`, `href="gallery"`}},
+		{Path: "/gallery", Contains: []string{"<h1>Uploads</h1>", `href="./"`}},
+	})
+}
@@ -0,0 +1,249 @@
+package components
+
+import (
+	"bytes"
+	"image"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+
+	"github.com/mlctrez/imgtofactbp/components/clipboard"
+	"github.com/mlctrez/imgtofactbp/conversions"
+	"github.com/suntong/go-app-demos/0B3A-textarea/metadata"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+const ImageRenderWidth = 300
+
+// appControl is a component that displays a simple "Hello World!". A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type appControl struct {
+	app.Compo
+	textStr   string
+	clipboard *clipboard.Clipboard
+	original  image.Image
+	scaled    image.Image
+	grayscale image.Image
+	inverted  bool
+
+	thresholdValue uint32
+
+	// stages are the images shown side by side once an image is pasted:
+	// scaled, grayscale, thresholded and the preview of the blueprint.
+	stages []stage
+
+	// bw is the thresholded image, the one turned into a blueprint.
+	bw *image.Gray
+
+	// kind and scale are the indexes of the item and the scale of the
+	// blueprint, in blueprint.Kinds and blueprint.Scales.
+	kind, scale int
+
+	// blueprint is the blueprint string last copied, and copyStatus tells
+	// how copying it went.
+	blueprint  string
+	copyStatus string
+
+	// pasteStatus tells why the last pasted image could not be processed.
+	pasteStatus string
+
+	// pasted is the data of the pasted image, and info its metadata.
+	// stripStatus tells how downloading it without them went.
+	pasted      []byte
+	info        *metadata.Info
+	stripStatus string
+
+	// pasteErrors are the errors reading or decoding the pasted image, or
+	// parsing its metadata.
+	pasteErrors []string
+
+	// upload is the upload of the pasted image to the server, nil until an
+	// image is pasted.
+	upload *upload.Progress
+}
+
+func (uc *appControl) OnMount(ctx app.Context) {
+	uc.clipboard.HandlePaste(ctx, "image/", func(data *clipboard.PasteData) {
+		uc.imagePaste(ctx, data)
+	})
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (uc *appControl) Render() app.UI {
+	if uc.clipboard == nil {
+		uc.clipboard = &clipboard.Clipboard{ID: "clipboard"}
+		uc.thresholdValue = defaultThreshold
+		uc.scale = defaultScale
+	}
+	return app.Div().Body(
+		uc.clipboard,
+		app.If(uc.textStr != "",
+			app.Textarea().Text(uc.textStr).Cols(80).ReadOnly(true),
+		).Else(
+			app.P().Body(
+				app.Textarea().
+					Text(uc.textStr).
+					Spellcheck(true).
+					Style("border", "solid 1px orange;").
+					Placeholder("Paste your text").
+					AutoFocus(true).
+					OnChange(uc.ValueTo(&uc.textStr)),
+			),
+		),
+		uc.imagesRow(),
+		uc.statusLine(),
+		uc.uploadLine(),
+		uc.metadataPanel(),
+	)
+}
+
+func (uc *appControl) imagesRow() app.UI {
+	if len(uc.stages) == 0 {
+		return app.Div().Style("display", "flex").Body(
+			app.Img().ID("uploadedImage").Src("/web/logo-512.png").Width(ImageRenderWidth).
+				Style("cursor", "pointer"),
+		)
+	}
+	return app.Div().Body(
+		app.Div().Style("display", "flex").Body(
+			app.Range(uc.stages).Slice(func(i int) app.UI {
+				s := uc.stages[i]
+				return app.Figure().Body(
+					app.Img().ID(s.ID).Src(s.Src).Width(ImageRenderWidth).Alt(s.Name),
+					app.FigCaption().Body(
+						app.Text(s.Name+" "),
+						app.A().Href(s.Src).Download(s.Filename()).Text("PNG"),
+					),
+				)
+			}),
+		),
+		app.P().Body(
+			app.Label().Body(
+				app.Text("Threshold "),
+				app.Input().Type("range").ID("threshold").
+					Min(0).Max(maxThreshold).Step(256).Value(uc.thresholdValue).
+					OnInput(uc.onThreshold),
+			),
+			app.Label().Body(
+				app.Input().Type("checkbox").ID("invert").Checked(uc.inverted).
+					OnChange(uc.onInvert),
+				app.Text(" Invert"),
+			),
+		),
+		uc.blueprintControls(),
+	)
+}
+
+// statusLine renders why the last pasted image could not be processed, or
+// nothing when it was.
+func (uc *appControl) statusLine() app.UI {
+	if uc.pasteStatus == "" {
+		return nil
+	}
+	return app.P().ID("pasteStatus").Style("color", "red").Text(uc.pasteStatus)
+}
+
+// uploadLine renders the upload of the pasted image, if any, and the link
+// to the gallery of the uploads.
+func (uc *appControl) uploadLine() app.UI {
+	var progress app.UI
+	if uc.upload != nil {
+		progress = app.Span().Body(uc.upload.UI(), app.Text(" "))
+	}
+	return app.P().ID("upload").Body(
+		progress,
+		// Relative, to stay under the path prefix the demo is mounted at,
+		// such as the one of the gallery.
+		app.A().Href(strings.TrimPrefix(upload.GalleryRoute, "/")).Text("Uploaded images"),
+	)
+}
+
+func (uc *appControl) imagePaste(ctx app.Context, data *clipboard.PasteData) {
+	pasted, err := conversions.Base64ToByte(data.Data)
+	if err != nil {
+		uc.pasteFailed(ctx, nil, nil, "Reading the pasted image failed: "+err.Error())
+		return
+	}
+	// The metadata the decoding discards, and what can be told of an image
+	// that does not decode.
+	var errs []string
+	info, err := metadata.Parse(pasted)
+	if err != nil {
+		errs = append(errs, "Parsing the metadata failed: "+err.Error())
+	}
+	pastedImage, _, err := image.Decode(bytes.NewReader(pasted))
+	if err != nil {
+		uc.pasteFailed(ctx, pasted, info, append(errs, "Decoding the pasted image failed: "+err.Error())...)
+		return
+	}
+	// The image is saved on the server meanwhile; the server checks it
+	// again.
+	progress := &upload.Progress{}
+	ctx.Dispatch(func(ctx app.Context) {
+		uc.upload = progress
+	})
+	ctx.Async(func() {
+		progress.Send(ctx, data.Type, pasted)
+	})
+	// Resizing a large image takes a while: the page stays responsive
+	// meanwhile.
+	ctx.Async(func() {
+		scaled, gray, err := prepare(pastedImage)
+		if err != nil {
+			ctx.Dispatch(func(ctx app.Context) {
+				uc.pasteStatus = "Processing the pasted image failed: " + err.Error()
+			})
+			return
+		}
+		stages := []stage{
+			{ID: "uploadedImage", Name: "Resized", Src: conversions.ImageToBase64(scaled)},
+			{ID: "grayScaleImage", Name: "Grayscale", Src: conversions.ImageToBase64(gray)},
+		}
+		ctx.Dispatch(func(ctx app.Context) {
+			uc.original, uc.scaled, uc.grayscale = pastedImage, scaled, gray
+			uc.pasted, uc.info, uc.stripStatus = pasted, info, ""
+			uc.pasteStatus, uc.pasteErrors = "", errs
+			uc.stages = stages
+			uc.renderThreshold()
+		})
+	})
+}
+
+// pasteFailed shows errs in the metadata panel, along with the metadata info
+// of the pasted image if any, in place of the images of the previous one.
+func (uc *appControl) pasteFailed(ctx app.Context, pasted []byte, info *metadata.Info, errs ...string) {
+	ctx.Dispatch(func(ctx app.Context) {
+		uc.pasted, uc.info, uc.stripStatus = pasted, info, ""
+		uc.pasteStatus, uc.pasteErrors = "", errs
+		uc.stages, uc.bw, uc.upload = nil, nil, nil
+	})
+}
+
+func (uc *appControl) onThreshold(ctx app.Context, e app.Event) {
+	uc.thresholdValue = uint32(ctx.JSSrc().Get("value").Int())
+	uc.renderThreshold()
+}
+
+func (uc *appControl) onInvert(ctx app.Context, e app.Event) {
+	uc.inverted = ctx.JSSrc().Get("checked").Bool()
+	uc.renderThreshold()
+}
+
+// renderThreshold sets the thresholded image from the grayscale one, and
+// the blueprint of it.
+func (uc *appControl) renderThreshold() {
+	uc.bw = threshold(uc.grayscale, uc.thresholdValue, uc.inverted)
+	uc.setStage(2, stage{ID: "thresholdImage", Name: "Threshold", Src: conversions.ImageToBase64(uc.bw)})
+	uc.renderBlueprint()
+}
+
+// setStage sets the stage i, after those before it.
+func (uc *appControl) setStage(i int, s stage) {
+	if i < len(uc.stages) {
+		uc.stages[i] = s
+	} else {
+		uc.stages = append(uc.stages, s)
+	}
+}
@@ -1,16 +1,21 @@
-module project
+module github.com/suntong/go-app-demos/0B3A-textarea
 
 go 1.21
 
 require (
 	github.com/maxence-charriere/go-app/v9 v9.8.0
 	github.com/mlctrez/imgtofactbp v1.0.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
 )
 
 require (
+	github.com/akrylysov/algnhsa v1.0.0 // indirect
+	github.com/aws/aws-lambda-go v1.37.0 // indirect
 	github.com/deckarep/golang-set v1.7.1 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/mlctrez/edgeefy v0.0.0-20210214182222-402531e31b4f // indirect
 	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646 // indirect
 	gonum.org/v1/gonum v0.9.3 // indirect
 )
+
+replace github.com/suntong/go-app-demos/pkg => ../pkg
@@ -1,122 +1,54 @@
+// Command 0B3A-textarea takes the image pasted into its text area through a
+// pipeline, run in Go in the browser: resized to 300 pixels wide, in
+// grayscale, then black and white with a live threshold slider and an invert
+// toggle. Each stage shows side by side, downloadable as PNG.
+//
+// The black and white image then makes a Factorio blueprint (see package
+// blueprint), of tiles, walls or lamps, one per pixel or per block of pixels:
+// its preview grid shows next to the other stages, and a button copies its
+// blueprint string.
+//
+// A metadata panel (see package metadata) shows what the pasted image
+// carries: JPEG EXIF, with its camera and GPS location linked to a map, PNG
+// text chunks, dimensions and color model; a button downloads the image
+// re-encoded without any of it.
+//
+// The pasted image is also uploaded to the server, and listed at /gallery, as
+// in 0B3B-textarea (see pkg/upload); -uploads sets the directory the images
+// are stored in.
 package main
 
 import (
-	"fmt"
-	"image"
-	"log"
-	"net/http"
+	"io/fs"
 
-	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B3A-textarea/components"
 
-	"github.com/mlctrez/imgtofactbp/components/clipboard"
-	"github.com/mlctrez/imgtofactbp/conversions"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/server"
+	"github.com/suntong/go-app-demos/pkg/upload"
 )
 
-const ImageRenderWidth = 300
-
-// appControl is a component that displays a simple "Hello World!". A component is a
-// customizable, independent, and reusable UI element. It is created by
-// embedding app.Compo into a struct.
-type appControl struct {
-	app.Compo
-	textStr   string
-	clipboard *clipboard.Clipboard
-	original  image.Image
-	scaled    image.Image
-	grayscale image.Image
-	inverted  bool
-
-	//threshold      *slider.Continuous
-	thresholdValue uint32
-}
-
-func (uc *appControl) OnMount(ctx app.Context) {
-	uc.clipboard.HandlePaste(ctx, "image/", uc.imagePaste)
-}
-
-// The Render method is where the component appearance is defined. Here, a
-// "Hello World!" is displayed as a heading.
-func (uc *appControl) Render() app.UI {
-	if uc.clipboard == nil {
-		uc.clipboard = &clipboard.Clipboard{ID: "clipboard"}
-	}
-	return app.Div().Body(
-		uc.clipboard,
-		app.If(uc.textStr != "",
-			app.Textarea().Text(uc.textStr).Cols(80).ReadOnly(true),
-		).Else(
-			app.P().Body(
-				app.Textarea().
-					Text(uc.textStr).
-					Spellcheck(true).
-					Style("border", "solid 1px orange;").
-					Placeholder("Paste your text").
-					AutoFocus(true).
-					OnChange(uc.ValueTo(&uc.textStr)),
-			),
-		),
-		uc.imagesRow(),
-	)
-}
-
-func (uc *appControl) imagesRow() app.HTMLDiv {
-	return app.Div().Style("display", "flex").Body(
-		app.Img().ID("uploadedImage").Src("/web/logo-512.png").Width(ImageRenderWidth).
-			Style("cursor", "pointer"),
-	)
-}
-
-func (uc *appControl) imagePaste(data *clipboard.PasteData) {
-	pastedImage, _, err := conversions.Base64ToImage(data.Data)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	uc.original = pastedImage
-	uc.renderImages()
-}
-
-func (uc *appControl) renderImages() {
-	// normalize image width to 400px
-	//uc.scaled = resize.Resize(ImageRenderWidth, 0, uc.original, resize.Lanczos3)
-	setImageSrc("uploadedImage", conversions.ImageToBase64(uc.original))
-}
-
-func setImageSrc(id string, src string) {
-	app.Window().GetElementByID(id).Set("src", src)
-}
+// web holds the web directory the resources are served from: the one of the
+// working directory, unless built with -tags embed (see embed.go).
+var web fs.FS
 
 // The main function is the entry point where the app is configured and started.
 // It is executed in 2 different environments: A client (the web browser) and a
 // server.
 func main() {
-	// The first thing to do is to associate the appControl component with a path.
+	// server.Run associates the components with their paths, on both client
+	// and server-side, and launches the app when executed in the web browser.
 	//
-	// This is done by calling the Route() function,  which tells go-app what
-	// component to display for a given path, on both client and server-side.
-	app.Route("/", &appControl{})
-
-	// Once the routes set up, the next thing to do is to either launch the app
-	// or the server that serves the app.
-	//
-	// When executed on the server-side, RunWhenOnBrowser() does nothing, which
-	// lets room for server implementation without the need for precompiling
-	// instructions.
-	app.RunWhenOnBrowser()
-
-	// Finally, launching the server that serves the app is done by using the Go
-	// standard HTTP package.
-	//
-	// The Handler is an HTTP handler that serves the client and all its
-	// required resources to make it work into a web browser. Here it is
-	// configured to handle requests with a path that starts with "/".
-	http.Handle("/", &app.Handler{
-		Name:        "Hello",
-		Description: "An Hello World! example",
+	// On the server-side, it serves the app with the Handler, an HTTP handler
+	// that serves the client and all its required resources to make it work
+	// into a web browser, along with the upload API (see pkg/upload).
+	server.Run(server.Config{
+		Routes:    components.Routes,
+		Endpoints: upload.Endpoints(upload.Dir()),
+		Handler: &app.Handler{
+			Name:        "Hello",
+			Description: "An Hello World! example",
+		},
+		Web: web,
 	})
-
-	log.Println("Listening on http://:8000")
-	if err := http.ListenAndServe(":8000", nil); err != nil {
-		log.Fatal(err)
-	}
 }
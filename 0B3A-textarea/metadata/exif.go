@@ -0,0 +1,413 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The types of the values of the TIFF entries EXIF is made of.
+const (
+	typeByte      = 1
+	typeASCII     = 2
+	typeShort     = 3
+	typeLong      = 4
+	typeRational  = 5
+	typeUndefined = 7
+	typeSLong     = 9
+	typeSRational = 10
+)
+
+// typeSizes are the sizes of the values of the types, in bytes.
+var typeSizes = map[uint16]int{
+	typeByte: 1, typeASCII: 1, typeShort: 2, typeLong: 4, typeRational: 8,
+	typeUndefined: 1, typeSLong: 4, typeSRational: 8,
+}
+
+// The tags of the pointers to the sub-directories.
+const (
+	tagExifIFD    = 0x8769
+	tagGPSIFD     = 0x8825
+	tagInteropIFD = 0xa005
+)
+
+// maxIFDs caps the directories read, against the loops of malformed EXIF.
+const maxIFDs = 16
+
+var errEXIF = errors.New("malformed EXIF")
+
+// entry is an entry of an EXIF directory.
+type entry struct {
+	tag   uint16
+	typ   uint16
+	count int
+	// raw holds the count values.
+	raw   []byte
+	order binary.ByteOrder
+}
+
+// uints returns the values of an entry of integers.
+func (e entry) uints() []int64 {
+	var v []int64
+	for i := 0; i < e.count; i++ {
+		switch e.typ {
+		case typeByte, typeUndefined:
+			v = append(v, int64(e.raw[i]))
+		case typeShort:
+			v = append(v, int64(e.order.Uint16(e.raw[2*i:])))
+		case typeLong:
+			v = append(v, int64(e.order.Uint32(e.raw[4*i:])))
+		case typeSLong:
+			v = append(v, int64(int32(e.order.Uint32(e.raw[4*i:]))))
+		}
+	}
+	return v
+}
+
+// rationals returns the values of an entry of rationals, as numerators and
+// denominators.
+func (e entry) rationals() [][2]int64 {
+	var v [][2]int64
+	for i := 0; i < e.count && (e.typ == typeRational || e.typ == typeSRational); i++ {
+		n, d := e.order.Uint32(e.raw[8*i:]), e.order.Uint32(e.raw[8*i+4:])
+		if e.typ == typeSRational {
+			v = append(v, [2]int64{int64(int32(n)), int64(int32(d))})
+		} else {
+			v = append(v, [2]int64{int64(n), int64(d)})
+		}
+	}
+	return v
+}
+
+// floats returns the values of an entry of rationals, or of integers.
+func (e entry) floats() []float64 {
+	var v []float64
+	for _, r := range e.rationals() {
+		if r[1] != 0 {
+			v = append(v, float64(r[0])/float64(r[1]))
+		}
+	}
+	for _, n := range e.uints() {
+		v = append(v, float64(n))
+	}
+	return v
+}
+
+func (e entry) text() string {
+	return strings.TrimRight(string(e.raw), "\x00 ")
+}
+
+// readEXIF reads the EXIF of the TIFF data b: its directories, the image
+// one, its sub-directories and the thumbnail one.
+func (info *Info) readEXIF(b []byte) {
+	if len(b) < 8 {
+		info.warn("%v: %d bytes", errEXIF, len(b))
+		return
+	}
+	var order binary.ByteOrder
+	switch string(b[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		info.warn("%v: byte order %q", errEXIF, b[:2])
+		return
+	}
+	if order.Uint16(b[2:]) != 42 {
+		info.warn("%v: not TIFF", errEXIF)
+		return
+	}
+
+	type ifd struct {
+		group  string
+		offset uint32
+	}
+	todo := []ifd{{"Image", order.Uint32(b[4:])}}
+	seen := map[uint32]bool{}
+	var gps []entry
+	for n := 0; len(todo) > 0 && n < maxIFDs; n++ {
+		d := todo[0]
+		todo = todo[1:]
+		if seen[d.offset] {
+			continue
+		}
+		seen[d.offset] = true
+		entries, next, err := readIFD(b, d.offset, order)
+		if err != nil {
+			info.warn("%s: %v", d.group, err)
+			continue
+		}
+		if d.group == "Image" && next != 0 {
+			todo = append(todo, ifd{"Thumbnail", next})
+		}
+		for _, e := range entries {
+			switch {
+			case e.tag == tagExifIFD && len(e.uints()) == 1:
+				todo = append(todo, ifd{"Photo", uint32(e.uints()[0])})
+			case e.tag == tagGPSIFD && len(e.uints()) == 1:
+				todo = append(todo, ifd{"GPS", uint32(e.uints()[0])})
+			case e.tag == tagInteropIFD && len(e.uints()) == 1:
+				todo = append(todo, ifd{"Interoperability", uint32(e.uints()[0])})
+			default:
+				info.add(d.group, tagName(d.group, e.tag), formatEntry(d.group, e))
+			}
+			if v := e.uints(); d.group == "Image" && e.tag == tagOrientation && len(v) == 1 {
+				if o := int(v[0]); o >= 1 && o <= 8 {
+					info.Orientation = o
+				}
+			}
+		}
+		if d.group == "GPS" {
+			gps = entries
+		}
+	}
+	info.Location = location(gps)
+}
+
+// readIFD returns the entries of the directory at offset in b, and the offset
+// of the next directory.
+func readIFD(b []byte, offset uint32, order binary.ByteOrder) ([]entry, uint32, error) {
+	if int64(offset)+2 > int64(len(b)) {
+		return nil, 0, errEXIF
+	}
+	n := int(order.Uint16(b[offset:]))
+	start := int(offset) + 2
+	if start+12*n+4 > len(b) {
+		return nil, 0, errEXIF
+	}
+	var entries []entry
+	for i := 0; i < n; i++ {
+		p := b[start+12*i:]
+		e := entry{
+			tag:   order.Uint16(p),
+			typ:   order.Uint16(p[2:]),
+			count: int(order.Uint32(p[4:])),
+			order: order,
+		}
+		size, ok := typeSizes[e.typ]
+		if !ok {
+			// An unknown type, which cannot be sized.
+			continue
+		}
+		total := int64(size) * int64(e.count)
+		if total <= 4 {
+			e.raw = p[8 : 8+total]
+		} else {
+			off := int64(order.Uint32(p[8:]))
+			if off+total > int64(len(b)) {
+				return entries, 0, fmt.Errorf("%w: entry 0x%04x out of bounds", errEXIF, e.tag)
+			}
+			e.raw = b[off : off+total]
+		}
+		entries = append(entries, e)
+	}
+	return entries, order.Uint32(b[start+12*n:]), nil
+}
+
+// location returns the location of the GPS entries, if they tell it.
+func location(gps []entry) *Location {
+	var l Location
+	var lat, lon, latRef, lonRef bool
+	for _, e := range gps {
+		v := e.floats()
+		switch e.tag {
+		case 0x1: // GPSLatitudeRef
+			latRef = e.text() == "S"
+		case 0x2: // GPSLatitude
+			l.Latitude, lat = degrees(v), len(v) == 3
+		case 0x3: // GPSLongitudeRef
+			lonRef = e.text() == "W"
+		case 0x4: // GPSLongitude
+			l.Longitude, lon = degrees(v), len(v) == 3
+		case 0x6: // GPSAltitude
+			if len(v) == 1 {
+				l.Altitude, l.HasAltitude = v[0], true
+			}
+		}
+	}
+	if !lat || !lon {
+		return nil
+	}
+	if latRef {
+		l.Latitude = -l.Latitude
+	}
+	if lonRef {
+		l.Longitude = -l.Longitude
+	}
+	for _, e := range gps {
+		// GPSAltitudeRef, 1 below the sea level.
+		if e.tag == 0x5 && len(e.uints()) == 1 && e.uints()[0] == 1 {
+			l.Altitude = -l.Altitude
+		}
+	}
+	return &l
+}
+
+// degrees returns the degrees, minutes and seconds of dms in degrees.
+func degrees(dms []float64) float64 {
+	if len(dms) != 3 {
+		return 0
+	}
+	return dms[0] + dms[1]/60 + dms[2]/3600
+}
+
+const tagOrientation = 0x112
+
+// The names of the common tags of the image and thumbnail directories
+// (TIFF), of the photo one (Exif), of the GPS one and of the
+// interoperability one, as EXIF names them.
+var (
+	imageTags = map[uint16]string{
+		0x0100: "ImageWidth", 0x0101: "ImageLength", 0x0103: "Compression",
+		0x010e: "ImageDescription", 0x010f: "Make", 0x0110: "Model",
+		0x0112: "Orientation", 0x011a: "XResolution", 0x011b: "YResolution",
+		0x0128: "ResolutionUnit", 0x0131: "Software", 0x0132: "DateTime",
+		0x013b: "Artist", 0x0201: "JPEGInterchangeFormat",
+		0x0202: "JPEGInterchangeFormatLength", 0x0213: "YCbCrPositioning",
+		0x8298: "Copyright",
+	}
+	photoTags = map[uint16]string{
+		0x829a: "ExposureTime", 0x829d: "FNumber", 0x8822: "ExposureProgram",
+		0x8827: "ISOSpeedRatings", 0x9000: "ExifVersion", 0x9003: "DateTimeOriginal",
+		0x9004: "DateTimeDigitized", 0x9010: "OffsetTime", 0x9011: "OffsetTimeOriginal",
+		0x9101: "ComponentsConfiguration", 0x9201: "ShutterSpeedValue",
+		0x9202: "ApertureValue", 0x9203: "BrightnessValue", 0x9204: "ExposureBiasValue",
+		0x9205: "MaxApertureValue", 0x9207: "MeteringMode", 0x9209: "Flash",
+		0x920a: "FocalLength", 0x927c: "MakerNote", 0x9286: "UserComment",
+		0x9290: "SubSecTime", 0x9291: "SubSecTimeOriginal", 0xa000: "FlashpixVersion",
+		0xa001: "ColorSpace", 0xa002: "PixelXDimension", 0xa003: "PixelYDimension",
+		0xa402: "ExposureMode", 0xa403: "WhiteBalance", 0xa405: "FocalLengthIn35mmFilm",
+		0xa406: "SceneCaptureType", 0xa420: "ImageUniqueID", 0xa430: "CameraOwnerName",
+		0xa431: "BodySerialNumber", 0xa432: "LensSpecification", 0xa433: "LensMake",
+		0xa434: "LensModel", 0xa435: "LensSerialNumber",
+	}
+	gpsTags = map[uint16]string{
+		0x00: "GPSVersionID", 0x01: "GPSLatitudeRef", 0x02: "GPSLatitude",
+		0x03: "GPSLongitudeRef", 0x04: "GPSLongitude", 0x05: "GPSAltitudeRef",
+		0x06: "GPSAltitude", 0x07: "GPSTimeStamp", 0x0c: "GPSSpeedRef", 0x0d: "GPSSpeed",
+		0x10: "GPSImgDirectionRef", 0x11: "GPSImgDirection", 0x12: "GPSMapDatum",
+		0x1b: "GPSProcessingMethod", 0x1d: "GPSDateStamp",
+	}
+	interopTags = map[uint16]string{
+		0x0001: "InteroperabilityIndex", 0x0002: "InteroperabilityVersion",
+	}
+)
+
+func tagName(group string, tag uint16) string {
+	tags := imageTags
+	switch group {
+	case "Photo":
+		tags = photoTags
+	case "GPS":
+		tags = gpsTags
+	case "Interoperability":
+		tags = interopTags
+	}
+	if name, ok := tags[tag]; ok {
+		return name
+	}
+	return fmt.Sprintf("Tag 0x%04x", tag)
+}
+
+// orientations are the names of the EXIF orientations.
+var orientations = [9]string{
+	1: "Horizontal (normal)",
+	2: "Mirror horizontal",
+	3: "Rotate 180",
+	4: "Mirror vertical",
+	5: "Mirror horizontal and rotate 270 CW",
+	6: "Rotate 90 CW",
+	7: "Mirror horizontal and rotate 90 CW",
+	8: "Rotate 270 CW",
+}
+
+// formatEntry returns the value of e, of the directory group, as text.
+func formatEntry(group string, e entry) string {
+	ints, rs := e.uints(), e.rationals()
+	switch {
+	case group != "GPS" && e.tag == tagOrientation && len(ints) == 1:
+		if o := ints[0]; o >= 1 && o <= 8 {
+			return orientations[o]
+		}
+	case group == "Photo" && e.tag == 0x829a && len(rs) == 1: // ExposureTime
+		return formatRational(rs[0]) + " s"
+	case group == "Photo" && e.tag == 0x829d && len(rs) == 1: // FNumber
+		return "f/" + formatRational(rs[0])
+	case group == "Photo" && e.tag == 0x920a && len(rs) == 1: // FocalLength
+		return formatRational(rs[0]) + " mm"
+	case group == "GPS" && e.tag == 0x06 && len(rs) == 1: // GPSAltitude
+		return formatRational(rs[0]) + " m"
+	case group == "GPS" && e.tag == 0x00 && e.typ == typeByte: // GPSVersionID
+		return joinInts(ints, ".")
+	case group == "GPS" && e.tag == 0x05 && len(ints) == 1: // GPSAltitudeRef
+		if ints[0] == 1 {
+			return "Below sea level"
+		}
+		return "Above sea level"
+	case group == "Photo" && e.tag == 0x9286 && e.count >= 8: // UserComment
+		// The first 8 bytes name the character code.
+		return strings.TrimRight(string(e.raw[8:]), "\x00 ")
+	}
+
+	switch e.typ {
+	case typeASCII:
+		return e.text()
+	case typeByte, typeUndefined:
+		if printable(e.raw) {
+			return e.text()
+		}
+		if e.typ == typeByte && e.count <= 4 {
+			return joinInts(ints, ", ")
+		}
+		return fmt.Sprintf("%d bytes", e.count)
+	case typeRational, typeSRational:
+		var s []string
+		for _, r := range rs {
+			s = append(s, formatRational(r))
+		}
+		return strings.Join(s, ", ")
+	default:
+		return joinInts(ints, ", ")
+	}
+}
+
+// formatRational returns r as a fraction when its numerator is 1, as the
+// exposure times are, and as a decimal number otherwise.
+func formatRational(r [2]int64) string {
+	n, d := r[0], r[1]
+	switch {
+	case d == 0:
+		return "undefined"
+	case n%d == 0:
+		return strconv.FormatInt(n/d, 10)
+	case n == 1:
+		return fmt.Sprintf("1/%d", d)
+	}
+	s := strconv.FormatFloat(float64(n)/float64(d), 'f', 4, 64)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+func joinInts(v []int64, sep string) string {
+	s := make([]string, len(v))
+	for i, n := range v {
+		s[i] = strconv.FormatInt(n, 10)
+	}
+	return strings.Join(s, sep)
+}
+
+// printable reports whether b is ASCII text, padded with NULs.
+func printable(b []byte) bool {
+	s := strings.TrimRight(string(b), "\x00")
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
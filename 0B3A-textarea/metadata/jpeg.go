@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// The JPEG markers of the segments read.
+const (
+	markerSOF0  = 0xc0
+	markerSOF2  = 0xc2
+	markerDHT   = 0xc4
+	markerDAC   = 0xcc
+	markerSOS   = 0xda
+	markerEOI   = 0xd9
+	markerAPP0  = 0xe0
+	markerAPP1  = 0xe1
+	markerAPP2  = 0xe2
+	markerAPP13 = 0xed
+	markerAPP14 = 0xee
+	markerCOM   = 0xfe
+)
+
+// The signatures the APPn segments start with.
+var (
+	sigJFIF      = []byte("JFIF\x00")
+	sigEXIF      = []byte("Exif\x00\x00")
+	sigXMP       = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	sigICC       = []byte("ICC_PROFILE\x00")
+	sigPhotoshop = []byte("Photoshop 3.0\x00")
+	sigAdobe     = []byte("Adobe")
+)
+
+// readJPEG reads the segments of the JPEG data b before its scan.
+func (info *Info) readJPEG(b []byte) {
+	const group = "JPEG"
+	for p := 2; p+4 <= len(b); {
+		if b[p] != 0xff {
+			info.warn("JPEG: no marker at %d", p)
+			return
+		}
+		marker := b[p+1]
+		switch {
+		case marker == 0xff:
+			// Fill byte.
+			p++
+			continue
+		case marker == markerSOS || marker == markerEOI:
+			return
+		case marker >= 0xd0 && marker <= 0xd7 || marker == 0x01:
+			// The markers without a segment.
+			p += 2
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(b[p+2:]))
+		if n < 2 || p+2+n > len(b) {
+			info.warn("JPEG: segment 0x%02x out of bounds", marker)
+			return
+		}
+		seg := b[p+4 : p+2+n]
+		p += 2 + n
+
+		switch {
+		case marker == markerAPP0 && bytes.HasPrefix(seg, sigJFIF) && len(seg) >= 12:
+			units := [...]string{"dots", "dots per inch", "dots per cm"}
+			unit := "unknown unit"
+			if int(seg[7]) < len(units) {
+				unit = units[seg[7]]
+			}
+			info.add(group, "JFIF", fmt.Sprintf("version %d.%02d, %dx%d %s",
+				seg[5], seg[6], binary.BigEndian.Uint16(seg[8:]), binary.BigEndian.Uint16(seg[10:]), unit))
+		case marker == markerAPP1 && bytes.HasPrefix(seg, sigEXIF):
+			info.readEXIF(seg[len(sigEXIF):])
+		case marker == markerAPP1 && bytes.HasPrefix(seg, sigXMP):
+			info.add(group, "XMP", fmt.Sprintf("%d bytes", len(seg)-len(sigXMP)))
+		case marker == markerAPP2 && bytes.HasPrefix(seg, sigICC):
+			info.add(group, "ICC profile", fmt.Sprintf("%d bytes", len(seg)-len(sigICC)-2))
+		case marker == markerAPP13 && bytes.HasPrefix(seg, sigPhotoshop):
+			info.add(group, "Photoshop (IPTC)", fmt.Sprintf("%d bytes", len(seg)-len(sigPhotoshop)))
+		case marker == markerAPP14 && bytes.HasPrefix(seg, sigAdobe):
+			info.add(group, "Adobe", fmt.Sprintf("%d bytes", len(seg)))
+		case marker > markerAPP0 && marker <= markerAPP0+15:
+			info.add(group, fmt.Sprintf("APP%d", marker-markerAPP0), fmt.Sprintf("%d bytes", len(seg)))
+		case marker == markerCOM:
+			info.add(group, "Comment", string(seg))
+		case marker >= markerSOF0 && marker <= markerSOF0+15 && marker != markerDHT && marker != 0xc8 && marker != markerDAC:
+			info.readSOF(marker, seg)
+		}
+	}
+}
+
+// readSOF reads the frame header seg, of the marker SOFn.
+func (info *Info) readSOF(marker byte, seg []byte) {
+	const group = "JPEG"
+	encoding := "Baseline"
+	switch {
+	case marker == markerSOF2:
+		encoding = "Progressive"
+	case marker != markerSOF0:
+		encoding = fmt.Sprintf("SOF%d", marker-markerSOF0)
+	}
+	info.add(group, "Encoding", encoding)
+	if len(seg) < 6 {
+		return
+	}
+	info.add(group, "Bits per sample", fmt.Sprint(seg[0]))
+	components := int(seg[5])
+	info.add(group, "Components", fmt.Sprint(components))
+	if components == 3 && len(seg) >= 6+3*3 {
+		// The sampling factors of the luma, over the ones of the chroma.
+		h, v := seg[7]>>4, seg[7]&0xf
+		hc, vc := seg[10]>>4, seg[10]&0xf
+		sub := fmt.Sprintf("%dx%d", h, v)
+		switch {
+		case hc == 0 || vc == 0:
+		case h == 2*hc && v == 2*vc:
+			sub = "4:2:0"
+		case h == 2*hc && v == vc:
+			sub = "4:2:2"
+		case h == hc && v == vc:
+			sub = "4:4:4"
+		case h == 4*hc && v == vc:
+			sub = "4:1:1"
+		}
+		info.add(group, "Chroma subsampling", sub)
+	}
+}
@@ -0,0 +1,161 @@
+// Package metadata reads the metadata an image carries, which decoding it
+// discards: the EXIF of JPEG and PNG images, with the camera and the GPS
+// location of photos, the text chunks of PNG images, the other JPEG
+// segments, and the dimensions and the color model of all. Strip re-encodes
+// an image without any.
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Field is an item of metadata.
+type Field struct {
+	// Group is where the field comes from: an EXIF directory, "Image",
+	// "Photo", "GPS", "Thumbnail" or "Interoperability", "PNG" or "PNG text"
+	// for the chunks of a PNG image, or "JPEG" for the segments of a JPEG
+	// one.
+	Group string
+	Name  string
+	Value string
+}
+
+// Info is the metadata of an image.
+type Info struct {
+	// Format is the one image.DecodeConfig tells: "jpeg", "png" or "gif".
+	Format        string
+	Width, Height int
+	ColorModel    string
+
+	// Fields are all the metadata, in the order of the image.
+	Fields []Field
+
+	// Camera are the fields of Fields about the camera and the exposure of
+	// a photo: make, model, lens, exposure time, aperture, ISO, focal length
+	// and date.
+	Camera []Field
+
+	// Location is where a photo was taken, if it tells.
+	Location *Location
+
+	// Orientation is the EXIF orientation of a photo, from 1 to 8, which
+	// tells how its pixels turn to show it upright; 0 if it has none.
+	Orientation int
+
+	// Warnings are the errors reading the metadata, which is partial then.
+	Warnings []string
+}
+
+// Location is the GPS location of a photo.
+type Location struct {
+	// In degrees, north and east being positive.
+	Latitude, Longitude float64
+
+	// Altitude is in meters above the sea level, if HasAltitude.
+	Altitude    float64
+	HasAltitude bool
+}
+
+func (l Location) String() string {
+	s := fmt.Sprintf("%.6f, %.6f", l.Latitude, l.Longitude)
+	if l.HasAltitude {
+		s += fmt.Sprintf(", %.1f m", l.Altitude)
+	}
+	return s
+}
+
+// MapURL returns the URL of the OpenStreetMap map of l.
+func (l Location) MapURL() string {
+	return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%.6f&mlon=%.6f#map=16/%.6f/%.6f",
+		l.Latitude, l.Longitude, l.Latitude, l.Longitude)
+}
+
+// Parse returns the metadata of the image data, a JPEG, PNG or GIF one. The
+// errors are the ones of images that cannot be decoded: malformed metadata
+// is reported in the Warnings of Info.
+func Parse(data []byte) (*Info, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{
+		Format:     format,
+		Width:      cfg.Width,
+		Height:     cfg.Height,
+		ColorModel: colorModelName(cfg.ColorModel),
+	}
+	switch format {
+	case "jpeg":
+		info.readJPEG(data)
+	case "png":
+		info.readPNG(data)
+	}
+	info.Camera = info.camera()
+	return info, nil
+}
+
+func (info *Info) add(group, name, value string) {
+	info.Fields = append(info.Fields, Field{Group: group, Name: name, Value: value})
+}
+
+func (info *Info) warn(format string, args ...any) {
+	info.Warnings = append(info.Warnings, fmt.Sprintf(format, args...))
+}
+
+// cameraFields are the group and the name of the fields of Info.Camera, in
+// order.
+var cameraFields = [][2]string{
+	{"Image", "Make"},
+	{"Image", "Model"},
+	{"Photo", "LensModel"},
+	{"Photo", "ExposureTime"},
+	{"Photo", "FNumber"},
+	{"Photo", "ISOSpeedRatings"},
+	{"Photo", "FocalLength"},
+	{"Photo", "DateTimeOriginal"},
+}
+
+func (info *Info) camera() []Field {
+	var fields []Field
+	for _, cf := range cameraFields {
+		for _, f := range info.Fields {
+			if f.Group == cf[0] && f.Name == cf[1] {
+				fields = append(fields, f)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// colorModelName returns the name of the color model m of an image.
+func colorModelName(m color.Model) string {
+	switch m {
+	case color.RGBAModel:
+		return "RGBA"
+	case color.RGBA64Model:
+		return "RGBA, 16-bit"
+	case color.NRGBAModel:
+		return "NRGBA"
+	case color.NRGBA64Model:
+		return "NRGBA, 16-bit"
+	case color.GrayModel:
+		return "Gray"
+	case color.Gray16Model:
+		return "Gray, 16-bit"
+	case color.YCbCrModel:
+		return "YCbCr"
+	case color.CMYKModel:
+		return "CMYK"
+	}
+	if p, ok := m.(color.Palette); ok {
+		return fmt.Sprintf("Paletted, %d colors", len(p))
+	}
+	return fmt.Sprintf("%T", m)
+}
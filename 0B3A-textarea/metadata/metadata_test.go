@@ -0,0 +1,235 @@
+package metadata
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"os"
+	"testing"
+)
+
+// The fixtures of testdata are written by testdata/gen.go: photo.jpg carries
+// the EXIF of a photo, text.png PNG text chunks and EXIF, and plain.gif
+// nothing.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		format        string
+		width, height int
+		colorModel    string
+		orientation   int
+		fields        []Field
+		camera        int
+	}{
+		{
+			name: "photo.jpg", format: "jpeg", width: 32, height: 16, colorModel: "YCbCr", orientation: 6,
+			fields: []Field{
+				{"Image", "Make", "Gopher Optics"},
+				{"Image", "Model", "GO-1"},
+				{"Image", "Orientation", "Rotate 90 CW"},
+				{"Image", "DateTime", "2024:05:01 12:34:56"},
+				{"Photo", "ExposureTime", "1/250 s"},
+				{"Photo", "FNumber", "f/2.8"},
+				{"Photo", "ISOSpeedRatings", "100"},
+				{"Photo", "ExifVersion", "0232"},
+				{"Photo", "FocalLength", "4.2 mm"},
+				{"Photo", "LensModel", "GO-1 back camera 4.2mm f/2.8"},
+				{"GPS", "GPSVersionID", "2.3.0.0"},
+				{"GPS", "GPSLatitude", "48, 51, 29.52"},
+				{"GPS", "GPSAltitudeRef", "Above sea level"},
+				{"GPS", "GPSAltitude", "35 m"},
+				{"JPEG", "Comment", "Made for the 0B3A tests"},
+				{"JPEG", "Chroma subsampling", "4:2:0"},
+			},
+			camera: 8,
+		},
+		{
+			name: "text.png", format: "png", width: 16, height: 8, colorModel: "RGBA",
+			fields: []Field{
+				{"PNG", "Color type", "RGB"},
+				{"PNG text", "Title", "Fixture"},
+				{"PNG text", "Author", "Gophér"},
+				{"PNG text", "Comment", "Compressed comment"},
+				{"PNG text", "Description (fr)", "Légende en UTF-8"},
+				{"PNG", "Modified", "2024-05-01 12:34:56 UTC"},
+				{"PNG", "Pixels per meter", "2835x2835 (72x72 dpi)"},
+				{"Image", "Make", "Gopher Optics"},
+				{"Image", "Model", "GO-1"},
+			},
+			camera: 2,
+		},
+		{name: "plain.gif", format: "gif", width: 8, height: 8, colorModel: "Paletted, 4 colors"},
+	} {
+		info, err := Parse(fixture(t, tc.name))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if info.Format != tc.format || info.Width != tc.width || info.Height != tc.height || info.ColorModel != tc.colorModel {
+			t.Errorf("%s: %s %dx%d %s, want %s %dx%d %s", tc.name,
+				info.Format, info.Width, info.Height, info.ColorModel, tc.format, tc.width, tc.height, tc.colorModel)
+		}
+		if info.Orientation != tc.orientation {
+			t.Errorf("%s: orientation %d, want %d", tc.name, info.Orientation, tc.orientation)
+		}
+		for _, want := range tc.fields {
+			if !hasField(info.Fields, want) {
+				t.Errorf("%s: no field %+v in %+v", tc.name, want, info.Fields)
+			}
+		}
+		if tc.fields == nil && len(info.Fields) != 0 {
+			t.Errorf("%s: fields %+v, want none", tc.name, info.Fields)
+		}
+		if len(info.Camera) != tc.camera {
+			t.Errorf("%s: camera %+v, want %d fields", tc.name, info.Camera, tc.camera)
+		}
+		if len(info.Warnings) != 0 {
+			t.Errorf("%s: warnings %q", tc.name, info.Warnings)
+		}
+	}
+}
+
+func hasField(fields []Field, f Field) bool {
+	for _, g := range fields {
+		if g == f {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLocation(t *testing.T) {
+	info, err := Parse(fixture(t, "photo.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc := info.Location
+	if loc == nil {
+		t.Fatal("no location")
+	}
+	// 48°51'29.52"N 2°17'40.2"E, 35 m.
+	if math.Abs(loc.Latitude-48.8582) > 1e-6 || math.Abs(loc.Longitude-2.2945) > 1e-6 || !loc.HasAltitude || loc.Altitude != 35 {
+		t.Errorf("location %+v", *loc)
+	}
+	if s := loc.String(); s != "48.858200, 2.294500, 35.0 m" {
+		t.Errorf("location %q", s)
+	}
+	if u := loc.MapURL(); u == "" {
+		t.Error("no map URL")
+	}
+
+	if info, _ := Parse(fixture(t, "text.png")); info.Location != nil {
+		t.Errorf("text.png: location %+v", *info.Location)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte("not an image")); err == nil {
+		t.Error("parsed text")
+	}
+
+	// Cut in its text chunks, the PNG image still has its header.
+	info, err := Parse(fixture(t, "text.png")[:70])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Warnings) == 0 {
+		t.Errorf("cut PNG: no warnings, fields %+v", info.Fields)
+	}
+
+	for _, b := range []string{
+		"",
+		"XX\x00\x2a\x00\x00\x00\x08",
+		"MM\x00\x2a\xff\xff\xff\xff",
+		"MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x0f\x00\x02\xff\xff\xff\xff\x00\x00\x00\x00",
+	} {
+		var info Info
+		info.readEXIF([]byte(b))
+		if len(info.Warnings) == 0 {
+			t.Errorf("EXIF %q: no warnings, fields %+v", b, info.Fields)
+		}
+	}
+}
+
+func TestStrip(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		mime          string
+		width, height int
+	}{
+		// Rotated upright.
+		{"photo.jpg", "image/jpeg", 16, 32},
+		{"text.png", "image/png", 16, 8},
+		{"plain.gif", "image/gif", 8, 8},
+	} {
+		b, mime, err := Strip(fixture(t, tc.name))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if mime != tc.mime {
+			t.Errorf("%s: %s, want %s", tc.name, mime, tc.mime)
+		}
+		info, err := Parse(b)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if info.Width != tc.width || info.Height != tc.height {
+			t.Errorf("%s: %dx%d, want %dx%d", tc.name, info.Width, info.Height, tc.width, tc.height)
+		}
+		for _, f := range info.Fields {
+			switch f.Group {
+			case "Image", "Photo", "GPS", "Thumbnail", "Interoperability", "PNG text":
+				t.Errorf("%s: field %+v kept", tc.name, f)
+			}
+			if f.Name == "Comment" || f.Name == "Modified" {
+				t.Errorf("%s: field %+v kept", tc.name, f)
+			}
+		}
+		if info.Location != nil || info.Orientation != 0 || len(info.Camera) != 0 {
+			t.Errorf("%s: stripped %+v", tc.name, info)
+		}
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// The top left quarter of the photo is red, on the top right once
+	// rotated 90° clockwise.
+	b, _, err := Strip(fixture(t, "photo.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	red := func(x, y int) bool {
+		r, g, _, _ := img.At(x, y).RGBA()
+		return r > 0xc000 && g < 0x4000
+	}
+	if !red(12, 4) || red(4, 4) || red(12, 28) {
+		t.Error("not rotated 90° clockwise")
+	}
+
+	// All the orientations turn a 2x1 image upright, into a 2x1 or a 1x2
+	// one.
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Pix[0], src.Pix[3], src.Pix[7] = 0xff, 0xff, 0xff
+	for o, want := range map[int]image.Point{
+		1: {0, 0}, 2: {1, 0}, 3: {1, 0}, 4: {0, 0},
+		5: {0, 0}, 6: {0, 0}, 7: {0, 1}, 8: {0, 1},
+	} {
+		dst := orient(src, o)
+		r, _, _, _ := dst.At(want.X, want.Y).RGBA()
+		if r != 0xffff {
+			t.Errorf("orientation %d: red pixel not at %v", o, want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package metadata
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// pngSignature is the 8 bytes a PNG image starts with.
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// maxText caps the text of a compressed chunk, against the zip bombs.
+const maxText = 1 << 20
+
+// pngColorTypes are the names of the color types of the IHDR chunk.
+var pngColorTypes = map[byte]string{
+	0: "Grayscale",
+	2: "RGB",
+	3: "Indexed",
+	4: "Grayscale and alpha",
+	6: "RGBA",
+}
+
+// readPNG reads the chunks of the PNG data b: its header, its text chunks,
+// its EXIF and the other ancillary chunks.
+func (info *Info) readPNG(b []byte) {
+	const group = "PNG"
+	for p := len(pngSignature); p+8 <= len(b); {
+		n := int(binary.BigEndian.Uint32(b[p:]))
+		typ := string(b[p+4 : p+8])
+		if n < 0 || p+12+n > len(b) {
+			info.warn("PNG: chunk %q out of bounds", typ)
+			return
+		}
+		data := b[p+8 : p+8+n]
+		p += 12 + n
+
+		switch typ {
+		case "IHDR":
+			if len(data) < 13 {
+				continue
+			}
+			info.add(group, "Bit depth", fmt.Sprint(data[8]))
+			if name, ok := pngColorTypes[data[9]]; ok {
+				info.add(group, "Color type", name)
+			}
+			if data[12] == 1 {
+				info.add(group, "Interlace", "Adam7")
+			}
+		case "tEXt", "zTXt", "iTXt":
+			key, text, err := pngText(typ, data)
+			if err != nil {
+				info.warn("PNG: %s: %v", typ, err)
+				continue
+			}
+			info.add("PNG text", key, text)
+		case "eXIf":
+			info.readEXIF(data)
+		case "tIME":
+			if len(data) < 7 {
+				continue
+			}
+			t := time.Date(int(binary.BigEndian.Uint16(data)), time.Month(data[2]), int(data[3]),
+				int(data[4]), int(data[5]), int(data[6]), 0, time.UTC)
+			info.add(group, "Modified", t.Format("2006-01-02 15:04:05 MST"))
+		case "pHYs":
+			if len(data) < 9 {
+				continue
+			}
+			x, y := binary.BigEndian.Uint32(data), binary.BigEndian.Uint32(data[4:])
+			if data[8] == 1 {
+				info.add(group, "Pixels per meter", fmt.Sprintf("%dx%d (%.0fx%.0f dpi)", x, y, float64(x)*0.0254, float64(y)*0.0254))
+			} else {
+				info.add(group, "Pixel aspect ratio", fmt.Sprintf("%d:%d", x, y))
+			}
+		case "gAMA":
+			if len(data) < 4 {
+				continue
+			}
+			info.add(group, "Gamma", fmt.Sprintf("%.5f", float64(binary.BigEndian.Uint32(data))/100000))
+		case "sRGB":
+			intents := []string{"Perceptual", "Relative colorimetric", "Saturation", "Absolute colorimetric"}
+			if len(data) == 1 && int(data[0]) < len(intents) {
+				info.add(group, "sRGB", intents[data[0]])
+			}
+		case "iCCP":
+			name, _, _ := strings.Cut(string(data), "\x00")
+			info.add(group, "ICC profile", name)
+		case "IEND":
+			return
+		default:
+			// The ancillary chunks start with a lowercase letter, the
+			// critical ones, such as IDAT, are image data.
+			if typ[0] >= 'a' && typ[0] <= 'z' {
+				info.add(group, "Chunk "+typ, fmt.Sprintf("%d bytes", n))
+			}
+		}
+	}
+}
+
+// pngText returns the keyword and the text of the text chunk data of type
+// typ: tEXt, zTXt or iTXt.
+func pngText(typ string, data []byte) (string, string, error) {
+	key, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return "", "", errors.New("no keyword")
+	}
+	switch typ {
+	case "tEXt":
+		return string(key), latin1(rest), nil
+	case "zTXt":
+		// The compression method, 0 for zlib.
+		if len(rest) < 1 {
+			return "", "", fmt.Errorf("%s: truncated", key)
+		}
+		text, err := inflate(rest[1:])
+		return string(key), latin1(text), err
+	}
+
+	// iTXt: the compression flag and method, the language tag, the
+	// translated keyword and the UTF-8 text.
+	if len(rest) < 2 {
+		return "", "", fmt.Errorf("%s: truncated", key)
+	}
+	compressed := rest[0] == 1
+	parts := bytes.SplitN(rest[2:], []byte{0}, 3)
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("%s: truncated", key)
+	}
+	text := parts[2]
+	if compressed {
+		var err error
+		if text, err = inflate(text); err != nil {
+			return "", "", err
+		}
+	}
+	name := string(key)
+	if lang := string(parts[0]); lang != "" {
+		name += " (" + lang + ")"
+	}
+	return name, string(text), nil
+}
+
+func inflate(b []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, maxText))
+}
+
+// latin1 returns the ISO-8859-1 text b as UTF-8.
+func latin1(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
@@ -0,0 +1,90 @@
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// JPEGQuality is the quality of the JPEG images Strip re-encodes.
+const JPEGQuality = 90
+
+// Strip returns the image data re-encoded in its format, without any
+// metadata, and its MIME type. The EXIF orientation of a photo is applied to
+// its pixels, so that it shows the same way without it. The frames of an
+// animated GIF are kept.
+func Strip(data []byte) ([]byte, string, error) {
+	info, err := Parse(data)
+	if err != nil {
+		return nil, "", err
+	}
+	var b bytes.Buffer
+	switch info.Format {
+	case "gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", err
+		}
+		err = gif.EncodeAll(&b, g)
+		return b.Bytes(), "image/gif", err
+	case "jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", err
+		}
+		err = jpeg.Encode(&b, orient(img, info.Orientation), &jpeg.Options{Quality: JPEGQuality})
+		return b.Bytes(), "image/jpeg", err
+	case "png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", err
+		}
+		err = png.Encode(&b, orient(img, info.Orientation))
+		return b.Bytes(), "image/png", err
+	}
+	return nil, "", fmt.Errorf("metadata: cannot strip %s images", info.Format)
+}
+
+// orient returns img turned upright, from the EXIF orientation o.
+func orient(img image.Image, o int) image.Image {
+	if o <= 1 || o > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		// Transposed.
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Rect, img, b.Min, draw.Src)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch o {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
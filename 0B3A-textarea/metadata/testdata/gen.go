@@ -0,0 +1,287 @@
+//go:build ignore
+
+// Gen writes the fixture images of the tests of the metadata package, from
+// the testdata directory:
+//
+//	go run gen.go
+//
+// photo.jpg carries the EXIF of a photo, big-endian: its camera, its
+// orientation, rotated 90° clockwise, and its GPS location. text.png carries
+// PNG text chunks, of the three kinds, its time and density, and EXIF,
+// little-endian. plain.gif carries nothing.
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"sort"
+)
+
+func main() {
+	write("photo.jpg", photo())
+	write("text.png", text())
+	write("plain.gif", plain())
+}
+
+func write(name string, b []byte) {
+	if err := os.WriteFile(name, b, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// quadrants returns an image whose top left quarter is red, and the others
+// white, which tells its orientation.
+func quadrants(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+			if x < w/2 && y < h/2 {
+				c = color.NRGBA{0xff, 0, 0, 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func photo() []byte {
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, quadrants(32, 16), &jpeg.Options{Quality: 95}); err != nil {
+		log.Fatal(err)
+	}
+	order := binary.BigEndian
+	exif := tiff(order,
+		[]field{
+			ascii(0x010f, "Gopher Optics"),
+			ascii(0x0110, "GO-1"),
+			short(0x0112, 6),
+			rational(0x011a, 72, 1),
+			rational(0x011b, 72, 1),
+			short(0x0128, 2),
+			ascii(0x0131, "gen.go"),
+			ascii(0x0132, "2024:05:01 12:34:56"),
+		},
+		[]field{
+			rational(0x829a, 1, 250),
+			rational(0x829d, 28, 10),
+			short(0x8827, 100),
+			{0x9000, 7, 4, []byte("0232")},
+			ascii(0x9003, "2024:05:01 12:34:56"),
+			rational(0x920a, 42, 10),
+			ascii(0xa434, "GO-1 back camera 4.2mm f/2.8"),
+		},
+		[]field{
+			{0x0000, 1, 4, []byte{2, 3, 0, 0}},
+			ascii(0x0001, "N"),
+			rational(0x0002, 48, 1, 51, 1, 2952, 100),
+			ascii(0x0003, "E"),
+			rational(0x0004, 2, 1, 17, 1, 4020, 100),
+			{0x0005, 1, 1, []byte{0}},
+			rational(0x0006, 35, 1),
+		},
+	)
+
+	// The APP1 segment of the EXIF, and a comment, after the SOI marker.
+	jpg := b.Bytes()
+	var out bytes.Buffer
+	out.Write(jpg[:2])
+	segment(&out, 0xe1, append([]byte("Exif\x00\x00"), exif...))
+	segment(&out, 0xfe, []byte("Made for the 0B3A tests"))
+	out.Write(jpg[2:])
+	return out.Bytes()
+}
+
+func segment(w *bytes.Buffer, marker byte, data []byte) {
+	w.Write([]byte{0xff, marker})
+	binary.Write(w, binary.BigEndian, uint16(len(data)+2))
+	w.Write(data)
+}
+
+func text() []byte {
+	var b bytes.Buffer
+	if err := png.Encode(&b, quadrants(16, 8)); err != nil {
+		log.Fatal(err)
+	}
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write([]byte("Compressed comment"))
+	zw.Close()
+	exif := tiff(binary.LittleEndian,
+		[]field{ascii(0x010f, "Gopher Optics"), ascii(0x0110, "GO-1")}, nil, nil)
+
+	// The chunks after the IHDR one, 8 bytes of signature and 25 of chunk
+	// in.
+	p := b.Bytes()
+	var out bytes.Buffer
+	out.Write(p[:33])
+	chunk(&out, "tEXt", []byte("Title\x00Fixture"))
+	chunk(&out, "tEXt", []byte("Author\x00Goph\xe9r"))
+	chunk(&out, "zTXt", append([]byte("Comment\x00\x00"), z.Bytes()...))
+	chunk(&out, "iTXt", []byte("Description\x00\x00\x00fr\x00Description\x00Légende en UTF-8"))
+	chunk(&out, "tIME", []byte{0x07, 0xe8, 5, 1, 12, 34, 56})
+	chunk(&out, "pHYs", []byte{0, 0, 0x0b, 0x13, 0, 0, 0x0b, 0x13, 1})
+	chunk(&out, "eXIf", exif)
+	out.Write(p[33:])
+	return out.Bytes()
+}
+
+func chunk(w *bytes.Buffer, typ string, data []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(data)))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+func plain() []byte {
+	palette := color.Palette{color.White, color.Black, color.NRGBA{0xff, 0, 0, 0xff}, color.NRGBA{0, 0, 0xff, 0xff}}
+	img := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i % 4)
+	}
+	var b bytes.Buffer
+	if err := gif.Encode(&b, img, nil); err != nil {
+		log.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// byteOrder is the byte order of the TIFF data.
+type byteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// field is an entry of a TIFF directory.
+type field struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func ascii(tag uint16, s string) field {
+	return field{tag, 2, uint32(len(s) + 1), append([]byte(s), 0)}
+}
+
+func short(tag, v uint16) field {
+	return field{tag, 3, 1, binary.BigEndian.AppendUint16(nil, v)}
+}
+
+func rational(tag uint16, v ...uint32) field {
+	var b []byte
+	for _, n := range v {
+		b = binary.BigEndian.AppendUint32(b, n)
+	}
+	return field{tag, 5, uint32(len(v) / 2), b}
+}
+
+// tiff returns the TIFF data of the image directory, and of its photo and
+// GPS sub-directories if any. The fields are big-endian, and swapped for the
+// little-endian order.
+func tiff(order byteOrder, image, photo, gps []field) []byte {
+	// The pointers to the sub-directories come first, to size the image
+	// directory.
+	if photo != nil {
+		image = append(image, field{0x8769, 4, 1, make([]byte, 4)})
+	}
+	if gps != nil {
+		image = append(image, field{0x8825, 4, 1, make([]byte, 4)})
+	}
+	size := func(fs []field) uint32 {
+		n := uint32(2 + 12*len(fs) + 4)
+		for _, f := range fs {
+			if len(f.data) > 4 {
+				n += uint32(len(f.data)+1) &^ 1
+			}
+		}
+		return n
+	}
+	offImage := uint32(8)
+	offPhoto := offImage + size(image)
+	offGPS := offPhoto + size(photo)
+	for i := range image {
+		switch image[i].tag {
+		case 0x8769:
+			image[i].data = binary.BigEndian.AppendUint32(nil, offPhoto)
+		case 0x8825:
+			image[i].data = binary.BigEndian.AppendUint32(nil, offGPS)
+		}
+	}
+
+	b := []byte("MM\x00\x2a")
+	if order == binary.LittleEndian {
+		b = []byte("II\x2a\x00")
+	}
+	b = order.AppendUint32(b, offImage)
+	for _, d := range []struct {
+		fields []field
+		offset uint32
+	}{{image, offImage}, {photo, offPhoto}, {gps, offGPS}} {
+		if d.fields == nil {
+			continue
+		}
+		b = ifd(b, order, d.fields, d.offset)
+	}
+	return b
+}
+
+// ifd appends the directory of fields, at offset, with the values that do
+// not fit in the entries after it.
+func ifd(b []byte, order byteOrder, fields []field, offset uint32) []byte {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].tag < fields[j].tag })
+	extra := offset + uint32(2+12*len(fields)+4)
+	var data []byte
+	b = order.AppendUint16(b, uint16(len(fields)))
+	for _, f := range fields {
+		v := swap(order, f)
+		b = order.AppendUint16(b, f.tag)
+		b = order.AppendUint16(b, f.typ)
+		b = order.AppendUint32(b, f.count)
+		if len(v) <= 4 {
+			b = append(b, append(v, make([]byte, 4-len(v))...)...)
+			continue
+		}
+		b = order.AppendUint32(b, extra+uint32(len(data)))
+		data = append(data, v...)
+		if len(v)%2 == 1 {
+			data = append(data, 0)
+		}
+	}
+	// No next directory.
+	b = order.AppendUint32(b, 0)
+	return append(b, data...)
+}
+
+// swap returns the big-endian data of f in order.
+func swap(order byteOrder, f field) []byte {
+	if order == binary.BigEndian {
+		return f.data
+	}
+	v := make([]byte, len(f.data))
+	switch f.typ {
+	case 3:
+		for i := 0; i < len(v); i += 2 {
+			order.PutUint16(v[i:], binary.BigEndian.Uint16(f.data[i:]))
+		}
+	case 4, 5:
+		for i := 0; i < len(v); i += 4 {
+			order.PutUint32(v[i:], binary.BigEndian.Uint32(f.data[i:]))
+		}
+	default:
+		copy(v, f.data)
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package components
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/0B3B-textarea/scan"
+	"github.com/suntong/go-app-demos/pkg/clipboard"
+)
+
+// scannedCode is a QR code or a barcode found in a pasted image, and the
+// status of its copy to the clipboard.
+type scannedCode struct {
+	scan.Code
+	CopyStatus string
+}
+
+// scanImage decodes the QR codes and the barcodes of img in the background.
+func (uc *appControl) scanImage(ctx app.Context, img *pastedImage) {
+	ctx.Async(func() {
+		var codes []*scannedCode
+		_, data, err := parseDataURL(img.Src)
+		if err == nil {
+			var m image.Image
+			if m, _, err = image.Decode(bytes.NewReader(data)); err == nil {
+				for _, c := range scan.Decode(m) {
+					codes = append(codes, &scannedCode{Code: c})
+				}
+			}
+		}
+		ctx.Dispatch(func(ctx app.Context) {
+			img.Codes, img.ScanErr, img.Scanned = codes, err, true
+		})
+	})
+}
+
+// renderCodes renders the codes found in img, each with a button copying
+// its text.
+func (uc *appControl) renderCodes(img *pastedImage) app.UI {
+	switch {
+	case !img.Scanned:
+		return app.Div().Text("Scanning for codes...")
+	case img.ScanErr != nil:
+		return app.Div().Class("error").Text("Not scanned: " + img.ScanErr.Error())
+	case len(img.Codes) == 0:
+		return app.Div().Text("No QR code or barcode")
+	}
+	return app.Div().Class("codes").Body(
+		app.Range(img.Codes).Slice(func(i int) app.UI {
+			c := img.Codes[i]
+			return app.Div().Class("code").Body(
+				app.Small().Text(c.Format),
+				app.Br(),
+				app.Code().Style("word-break", "break-all").Text(c.Text),
+				app.Br(),
+				app.Button().Text("Copy").OnClick(uc.onCopyCode(c)),
+				app.If(c.CopyStatus != "",
+					app.Text(" "+c.CopyStatus),
+				),
+			)
+		}),
+	)
+}
+
+func (uc *appControl) onCopyCode(c *scannedCode) app.EventHandler {
+	return func(ctx app.Context, e app.Event) {
+		c.CopyStatus = ""
+		ctx.Async(func() {
+			status := "Copied"
+			if err := clipboard.WriteText(ctx, c.Text); err != nil {
+				status = "Copy failed: " + err.Error()
+			}
+			ctx.Dispatch(func(ctx app.Context) {
+				c.CopyStatus = status
+			})
+		})
+	}
+}
+
+// qrSize is about the width of the QR codes generated from the text, in
+// pixels.
+const qrSize = 256
+
+// qrDataURL returns the PNG data URL of the QR code of text, at level M,
+// with its quiet zone, 4 modules wide.
+func qrDataURL(text string) (string, error) {
+	c, err := qr.Encode(text, qr.M, qr.Auto)
+	if err != nil {
+		return "", err
+	}
+	dim := c.Bounds().Dx()
+	scale := max(1, qrSize/(dim+8))
+	img := image.NewGray(image.Rect(0, 0, (dim+8)*scale, (dim+8)*scale))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for y := 0; y < dim; y++ {
+		for x := 0; x < dim; x++ {
+			if l, _, _, _ := color.GrayModel.Convert(c.At(x, y)).RGBA(); l < 0x8000 {
+				r := image.Rect(x+4, y+4, x+5, y+5)
+				draw.Draw(img, image.Rectangle{r.Min.Mul(scale), r.Max.Mul(scale)}, image.Black, image.Point{}, draw.Src)
+			}
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+func (uc *appControl) onInput(ctx app.Context, e app.Event) {
+	uc.typed = ctx.JSSrc().Get("value").String()
+	uc.updateQR()
+}
+
+func (uc *appControl) onGenerateQR(ctx app.Context, e app.Event) {
+	uc.generateQR = ctx.JSSrc().Get("checked").Bool()
+	uc.updateQR()
+}
+
+// updateQR generates the QR code of the text typed, when asked to.
+func (uc *appControl) updateQR() {
+	uc.qrSrc, uc.qrErr = "", nil
+	if uc.generateQR && uc.typed != "" {
+		uc.qrSrc, uc.qrErr = qrDataURL(uc.typed)
+	}
+}
+
+// renderQR renders the toggle of the QR code of the text typed, and the QR
+// code, or why there is none.
+func (uc *appControl) renderQR() app.UI {
+	toggle := app.Input().Type("checkbox").ID("generateQR").OnChange(uc.onGenerateQR)
+	if uc.generateQR {
+		toggle = toggle.Checked(true)
+	}
+	return app.Div().Body(
+		app.Label().Body(toggle, app.Text(" Generate a QR code from the text typed")),
+		app.If(uc.qrErr != nil,
+			app.P().Class("error").Text(fmt.Sprintf("No QR code: %v", uc.qrErr)),
+		).ElseIf(uc.qrSrc != "",
+			app.Figure().Body(
+				app.Img().ID("generatedQR").Src(uc.qrSrc).Alt("QR code of the text typed"),
+				app.FigCaption().Text(plural(len([]rune(uc.typed)), "character")),
+			),
+		),
+	)
+}
+
+// plural returns n followed by noun, in the plural unless n is 1.
+func plural(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", n, noun)
+}
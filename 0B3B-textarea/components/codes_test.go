@@ -0,0 +1,35 @@
+package components
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/suntong/go-app-demos/0B3B-textarea/scan"
+)
+
+func TestQRDataURL(t *testing.T) {
+	for _, text := range []string{"go-app", "https://github.com/suntong/go-app-demos", strings.Repeat("QR ", 200)} {
+		src, err := qrDataURL(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		typ, data, err := parseDataURL(src)
+		if err != nil || typ != "image/png" {
+			t.Fatalf("parseDataURL = %q, %v", typ, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		codes := scan.Decode(img)
+		if len(codes) != 1 || codes[0].Format != scan.QR || codes[0].Text != text {
+			t.Errorf("decoded %+v, want the QR code of %q", codes, text)
+		}
+	}
+
+	if _, err := qrDataURL(strings.Repeat("x", 5000)); err == nil {
+		t.Error("got the QR code of 5000 bytes, want an error")
+	}
+}
@@ -0,0 +1,149 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/interop"
+)
+
+// pasteAction is the action of the items pasted or dropped onto the text
+// area, their PasteData or the error reading them, tagged with the ID of the
+// text area.
+const pasteAction = "Clipboard:paste"
+
+// PasteData is the type and data pasted into the clipboard
+type PasteData struct {
+	Type string
+	Data string
+}
+
+// KindError is the error of an item pasted or dropped that is neither a
+// string nor a file.
+type KindError struct {
+	Kind string
+	Type string
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("%s: unsupported item kind %q", e.Type, e.Kind)
+}
+
+// TypeError is the error of an item whose type the text area does not
+// handle, such as text/rtf.
+type TypeError struct {
+	Type string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%s: unsupported type", e.Type)
+}
+
+// ReadError is the error reading the data of an item.
+type ReadError struct {
+	Type string
+	Err  error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Type, e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// pasteItem is an item of a DataTransfer, the clipboard data of a paste
+// event or the data of a drop. It is captured while the event is
+// dispatched: after it, the browsers empty the DataTransfer.
+type pasteItem struct {
+	kind string
+	typ  string
+
+	// text is called back with the data of a string item.
+	text *interop.Callback
+
+	// file is the File of a file item.
+	file app.Value
+}
+
+// captureItems returns the items of the DataTransfer dt. It must be called
+// by the listener of the event dt comes with.
+func captureItems(dt app.Value) []pasteItem {
+	if !dt.Truthy() {
+		return nil
+	}
+	list := dt.Get("items")
+	items := make([]pasteItem, list.Length())
+	for i := range items {
+		v := list.Index(i)
+		it := pasteItem{kind: v.Get("kind").String(), typ: v.Get("type").String()}
+		switch it.kind {
+		case "string":
+			it.text = interop.NewCallback()
+			v.Call("getAsString", it.text)
+		case "file":
+			it.file = v.Call("getAsFile")
+		}
+		items[i] = it
+	}
+	return items
+}
+
+// read returns the data of the item: the string of a string item, or the
+// data URL of a file. It blocks until the data is read, which the browser
+// does while the app goes on, or until ctx is done: it must not be called
+// from the UI goroutine.
+func (it pasteItem) read(ctx context.Context) (*PasteData, error) {
+	data := &PasteData{Type: it.typ}
+	switch it.kind {
+	case "string":
+		args, err := it.text.Wait(ctx)
+		if err != nil {
+			return nil, &ReadError{Type: it.typ, Err: err}
+		}
+		data.Data = args[0].String()
+	case "file":
+		if !it.file.Truthy() {
+			return nil, &ReadError{Type: it.typ, Err: errors.New("file unavailable")}
+		}
+		reader := app.Window().Get("FileReader").New()
+		loadEnd := interop.NewCallback()
+		reader.Set("onloadend", loadEnd)
+		reader.Call("readAsDataURL", it.file)
+		if _, err := loadEnd.Wait(ctx); err != nil {
+			// The callback, released as the wait gave up, is detached
+			// first: the abort would call it.
+			reader.Set("onloadend", nil)
+			reader.Call("abort")
+			return nil, &ReadError{Type: it.typ, Err: err}
+		}
+		if err := interop.ErrorOf(reader.Get("error")); err != nil {
+			return nil, &ReadError{Type: it.typ, Err: err}
+		}
+		data.Data = reader.Get("result").String()
+	default:
+		return nil, &KindError{Kind: it.kind, Type: it.typ}
+	}
+	return data, nil
+}
+
+// add routes the data of an item into the component: text/plain into
+// Content, text/html into HTML, and the images into Images, to be uploaded.
+func (uc *appControl) add(data *PasteData) error {
+	typ, _, _ := strings.Cut(data.Type, ";")
+	switch {
+	case typ == "text/plain":
+		uc.Content = data.Data
+	case typ == "text/html":
+		uc.HTML = data.Data
+	case strings.HasPrefix(typ, "image/"):
+		uc.Images = append(uc.Images, &pastedImage{Src: data.Data})
+	default:
+		return &TypeError{Type: data.Type}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	uc := &appControl{}
+	for _, d := range []*PasteData{
+		{Type: "text/plain", Data: "hello"},
+		{Type: "text/html; charset=utf-8", Data: "<b>hello</b>"},
+		{Type: "image/png", Data: "data:image/png;base64,AAAA"},
+		{Type: "image/jpeg", Data: "data:image/jpeg;base64,BBBB"},
+	} {
+		if err := uc.add(d); err != nil {
+			t.Errorf("add(%s): %v", d.Type, err)
+		}
+	}
+	if uc.Content != "hello" || uc.HTML != "<b>hello</b>" {
+		t.Errorf("content = %q, HTML = %q", uc.Content, uc.HTML)
+	}
+	if len(uc.Images) != 2 || uc.Images[1].Src != "data:image/jpeg;base64,BBBB" {
+		t.Errorf("images = %+v, want both images, in order", uc.Images)
+	}
+
+	err := uc.add(&PasteData{Type: "text/rtf", Data: "{\\rtf1}"})
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) || typeErr.Type != "text/rtf" {
+		t.Errorf("add(text/rtf): error = %v, want a TypeError", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&KindError{Kind: "other", Type: "x/y"}, `x/y: unsupported item kind "other"`},
+		{&TypeError{Type: "text/rtf"}, "text/rtf: unsupported type"},
+		{&ReadError{Type: "image/png", Err: io.ErrUnexpectedEOF}, "image/png: unexpected EOF"},
+	}
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("error = %q, want %q", got, test.want)
+		}
+	}
+	if err := (&ReadError{Err: io.ErrUnexpectedEOF}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadError does not wrap its error")
+	}
+}
+
+func TestReadKind(t *testing.T) {
+	_, err := pasteItem{kind: "other", typ: "x/y"}.read(context.Background())
+	var kindErr *KindError
+	if !errors.As(err, &kindErr) || kindErr.Kind != "other" {
+		t.Errorf("read: error = %v, want a KindError", err)
+	}
+}
+
+func TestParseDataURL(t *testing.T) {
+	typ, data, err := parseDataURL("data:image/png;base64,iVBORw==")
+	if err != nil || typ != "image/png" || string(data) != "\x89PNG" {
+		t.Errorf("parseDataURL = %q, %q, %v", typ, data, err)
+	}
+	for _, s := range []string{"image/png;base64,iVBORw==", "data:text/plain,hello", "data:image/png;base64,!"} {
+		if _, _, err := parseDataURL(s); err == nil {
+			t.Errorf("parseDataURL(%q): no error", s)
+		}
+	}
+}
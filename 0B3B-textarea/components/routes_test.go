@@ -0,0 +1,42 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+type uploads []upload.Upload
+
+func (u uploads) List() []upload.Upload {
+	return u
+}
+
+func TestRoutes(t *testing.T) {
+	id := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	upload.Uploads = uploads{{ID: id, Type: "image/png", Size: 3 << 10, Width: 64, Height: 48}}
+	defer func() { upload.Uploads = nil }()
+
+	servertest.Run(t, Routes, []servertest.Page{
+		{
+			Path:     "/",
+			Contains: []string{`id="pasteArea"`, `placeholder="Paste text or image here..."`, `href="gallery"`, `id="generateQR"`},
+			// Nothing is pasted yet; the page has the img of the go-app
+			// loader, though.
+			Excludes: []string{`class="gallery"`, `class="errors"`, `id="generatedQR"`},
+		},
+		{
+			Path: "/gallery",
+			Contains: []string{
+				"<h1>Uploads</h1>",
+				"1 upload",
+				`href="/uploads/` + id + `.png"`,
+				`src="/uploads/` + id + `.thumb.png"`,
+				"64x48, 3.0 KiB",
+				">Delete</button>",
+				`href="./"`,
+			},
+		},
+	})
+}
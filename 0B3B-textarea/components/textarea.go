@@ -0,0 +1,202 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+const (
+	// pasteAreaID is the ID of the text area, the one of the paste actions.
+	pasteAreaID = "pasteArea"
+
+	// readTimeout bounds the reading of the items of a paste or a drop, the
+	// large files taking a while.
+	readTimeout = 30 * time.Second
+)
+
+// appControl is a component that displays a simple text area. A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type appControl struct {
+	app.Compo
+	Content string
+	HTML    string
+	Images  []*pastedImage
+
+	// Errors are those of the items that could not be pasted or dropped.
+	Errors []string
+
+	dragging bool
+
+	// typed is the text typed into the text area, whose QR code is shown
+	// when generateQR, as qrSrc or qrErr.
+	typed      string
+	generateQR bool
+	qrSrc      string
+	qrErr      error
+
+	// listeners are the event listeners of the text area, removed on
+	// dismount.
+	listeners map[string]app.Func
+}
+
+func (uc *appControl) OnMount(ctx app.Context) {
+	app.Log("network status: mount - online")
+	ctx.Handle(pasteAction, uc.handlePaste)
+
+	// The listeners are JavaScript functions rather than go-app event
+	// handlers, which run once the event is over, when the browser has
+	// emptied its DataTransfer.
+	uc.listeners = map[string]app.Func{
+		"paste": app.FuncOf(func(this app.Value, args []app.Value) any {
+			args[0].Call("preventDefault")
+			uc.transfer(ctx, args[0].Get("clipboardData"))
+			return nil
+		}),
+		"drop": app.FuncOf(func(this app.Value, args []app.Value) any {
+			args[0].Call("preventDefault")
+			uc.transfer(ctx, args[0].Get("dataTransfer"))
+			uc.setDragging(ctx, false)
+			return nil
+		}),
+		// Cancelling dragover is what allows dropping.
+		"dragover": app.FuncOf(func(this app.Value, args []app.Value) any {
+			args[0].Call("preventDefault")
+			uc.setDragging(ctx, true)
+			return nil
+		}),
+		"dragleave": app.FuncOf(func(this app.Value, args []app.Value) any {
+			uc.setDragging(ctx, false)
+			return nil
+		}),
+	}
+	area := app.Window().GetElementByID(pasteAreaID)
+	for event, f := range uc.listeners {
+		area.Call("addEventListener", event, f)
+	}
+}
+
+func (uc *appControl) OnDismount() {
+	area := app.Window().GetElementByID(pasteAreaID)
+	for event, f := range uc.listeners {
+		if area.Truthy() {
+			area.Call("removeEventListener", event, f)
+		}
+		f.Release()
+	}
+	uc.listeners = nil
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (uc *appControl) Render() app.UI {
+	border := "solid 1px gray"
+	if uc.dragging {
+		border = "dashed 2px orange"
+	}
+	return app.Div().Body(
+		app.Textarea().
+			ID(pasteAreaID).
+			Placeholder("Paste text or image here...").
+			AutoFocus(true).
+			Style("width", "100%").
+			Style("height", "200px").
+			Style("border", border).
+			OnInput(uc.onInput),
+		uc.renderQR(),
+		app.If(uc.Content != "", app.Div().Text(uc.Content)),
+		app.If(uc.HTML != "",
+			app.Details().Body(
+				app.Summary().Text("HTML"),
+				app.Pre().Text(uc.HTML),
+			),
+		),
+		app.If(len(uc.Images) != 0,
+			app.Div().Class("gallery").Style("display", "flex").Style("flex-wrap", "wrap").Body(
+				app.Range(uc.Images).Slice(func(i int) app.UI {
+					img := uc.Images[i]
+					return app.Figure().Style("margin", "4px").Body(
+						app.Img().Src(img.Src).Style("max-width", "200px").Style("max-height", "200px"),
+						app.FigCaption().Style("max-width", "200px").Body(
+							img.Progress.UI(),
+							uc.renderCodes(img),
+						),
+					)
+				}),
+			),
+			app.Button().Text("Clear images").OnClick(uc.clearImages),
+		),
+		app.P().Body(
+			// Relative, to stay under the path prefix the demo is
+			// mounted at, such as the one of the gallery.
+			app.A().Href(strings.TrimPrefix(upload.GalleryRoute, "/")).Text("Uploaded images"),
+		),
+		app.If(len(uc.Errors) != 0,
+			app.Ul().Class("errors").Body(
+				app.Range(uc.Errors).Slice(func(i int) app.UI {
+					return app.Li().Text(uc.Errors[i])
+				}),
+			),
+		),
+	)
+}
+
+// transfer reads the items of the DataTransfer dt, and makes a paste action
+// of each one. It must be called by the listener of the event of dt.
+func (uc *appControl) transfer(ctx app.Context, dt app.Value) {
+	items := captureItems(dt)
+	ctx.Async(func() {
+		readCtx, cancel := context.WithTimeout(ctx, readTimeout)
+		defer cancel()
+		for _, it := range items {
+			var v any
+			data, err := it.read(readCtx)
+			if v = data; err != nil {
+				v = err
+			}
+			ctx.NewActionWithValue(pasteAction, v, app.T("id", pasteAreaID))
+		}
+	})
+}
+
+// handlePaste handles the paste actions, whose value is the PasteData of an
+// item or the error reading it.
+func (uc *appControl) handlePaste(ctx app.Context, a app.Action) {
+	if a.Tags.Get("id") != pasteAreaID {
+		return
+	}
+	var err error
+	switch v := a.Value.(type) {
+	case *PasteData:
+		n := len(uc.Images)
+		err = uc.add(v)
+		for _, img := range uc.Images[n:] {
+			uc.uploadImage(ctx, img)
+			uc.scanImage(ctx, img)
+		}
+	case error:
+		err = v
+	default:
+		err = fmt.Errorf("unexpected paste action value %T", v)
+	}
+	if err != nil {
+		app.Log(err)
+		uc.Errors = append(uc.Errors, err.Error())
+	}
+}
+
+func (uc *appControl) setDragging(ctx app.Context, dragging bool) {
+	ctx.Dispatch(func(ctx app.Context) {
+		uc.dragging = dragging
+	})
+}
+
+func (uc *appControl) clearImages(ctx app.Context, e app.Event) {
+	uc.Images = nil
+	uc.Errors = nil
+}
@@ -0,0 +1,56 @@
+package components
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/upload"
+)
+
+// pastedImage is an image pasted or dropped onto the text area, which is
+// uploaded to the server, and scanned for codes, as soon as it is.
+type pastedImage struct {
+	// The data URL of the image.
+	Src string
+
+	// The upload of the image to the server.
+	Progress upload.Progress
+
+	// The QR codes and barcodes of the image, once it is Scanned, or the
+	// error decoding it.
+	Codes   []*scannedCode
+	Scanned bool
+	ScanErr error
+}
+
+// uploadImage uploads img in the background, reporting the progress of its
+// chunks.
+func (uc *appControl) uploadImage(ctx app.Context, img *pastedImage) {
+	ctx.Async(func() {
+		typ, data, err := parseDataURL(img.Src)
+		if err != nil {
+			ctx.Dispatch(func(ctx app.Context) {
+				img.Progress.Err = err
+			})
+			return
+		}
+		img.Progress.Send(ctx, typ, data)
+	})
+}
+
+// parseDataURL returns the MIME type and the data of the base64 data URL s,
+// as FileReader.readAsDataURL makes them.
+func parseDataURL(s string) (string, []byte, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(s, "data:"), ",")
+	typ, isBase64 := strings.CutSuffix(header, ";base64")
+	if !ok || !isBase64 || !strings.HasPrefix(s, "data:") {
+		return "", nil, errors.New("not a base64 data URL")
+	}
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return typ, b, nil
+}
@@ -0,0 +1,206 @@
+// Package scan finds and decodes the QR codes and the 1D barcodes of an
+// image, such as a screenshot pasted into the 0B3B-textarea demo, with
+// gozxing, the Go port of ZXing. It is pure Go, so that the app decodes the
+// images in the web browser.
+//
+// The QR codes are found by their finder patterns, whatever their rotation,
+// and decoded with their error correction. The 1D barcodes, EAN-13, UPC-A,
+// EAN-8, Code 128 and Code 39, are read along the rows of the image, then
+// along its columns; the parts of the image around a barcode are searched
+// again, for the others.
+package scan
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/multi/qrcode"
+	"github.com/makiuchi-d/gozxing/oned"
+)
+
+// Format is the symbology of a code.
+type Format string
+
+const (
+	QR      Format = "QR Code"
+	EAN13   Format = "EAN-13"
+	UPCA    Format = "UPC-A"
+	EAN8    Format = "EAN-8"
+	Code128 Format = "Code 128"
+	Code39  Format = "Code 39"
+)
+
+// formats are the Formats of the gozxing ones.
+var formats = map[gozxing.BarcodeFormat]Format{
+	gozxing.BarcodeFormat_QR_CODE:  QR,
+	gozxing.BarcodeFormat_EAN_13:   EAN13,
+	gozxing.BarcodeFormat_UPC_A:    UPCA,
+	gozxing.BarcodeFormat_EAN_8:    EAN8,
+	gozxing.BarcodeFormat_CODE_128: Code128,
+	gozxing.BarcodeFormat_CODE_39:  Code39,
+}
+
+// Code is a code found in an image.
+type Code struct {
+	Format Format
+	Text   string
+
+	// The bounds of the code in the image: those of the points gozxing
+	// located it by, such as the centers of the finder patterns of a QR
+	// code, or the ends of the row a barcode was read along.
+	Bounds image.Rectangle
+}
+
+// hints make gozxing read the barcodes along the columns too, and tell
+// UPC-A from EAN-13.
+var hints = map[gozxing.DecodeHintType]interface{}{
+	gozxing.DecodeHintType_TRY_HARDER: true,
+	gozxing.DecodeHintType_POSSIBLE_FORMATS: []gozxing.BarcodeFormat{
+		gozxing.BarcodeFormat_QR_CODE,
+		gozxing.BarcodeFormat_EAN_13,
+		gozxing.BarcodeFormat_UPC_A,
+		gozxing.BarcodeFormat_EAN_8,
+		gozxing.BarcodeFormat_CODE_128,
+		gozxing.BarcodeFormat_CODE_39,
+	},
+}
+
+// maxDepth is the number of times the parts of the image around a barcode
+// are searched again, and minSide the size of the smallest part searched.
+const (
+	maxDepth = 4
+	minSide  = 20
+)
+
+// Decode returns the codes of img, top to bottom, then left to right. The
+// same code found twice, as the same barcode printed twice, is returned once.
+func Decode(img image.Image) []Code {
+	bm, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil
+	}
+	var codes []Code
+	// DecodeMultiple returns the QR codes decoded along with its error,
+	// which is that of the last one that could not be.
+	results, _ := qrcode.NewQRCodeMultiReader().DecodeMultiple(bm, hints)
+	for _, r := range results {
+		codes = add(codes, r, image.Point{})
+	}
+	qrs := len(codes)
+	for _, c := range decodeLinear(bm, image.Point{}, 0) {
+		// The rows of the modules of a QR code may pass for a barcode.
+		if !contains(codes, c) && !overlaps(codes[:qrs], c.Bounds) {
+			codes = append(codes, c)
+		}
+	}
+	codes = offset(codes, img.Bounds().Min)
+	sort.SliceStable(codes, func(i, j int) bool {
+		a, b := codes[i].Bounds.Min, codes[j].Bounds.Min
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return codes
+}
+
+// linearReaders read the 1D barcodes.
+func linearReaders() []gozxing.Reader {
+	return []gozxing.Reader{
+		oned.NewMultiFormatUPCEANReader(hints),
+		oned.NewCode128Reader(),
+		oned.NewCode39Reader(),
+	}
+}
+
+// decodeLinear returns the 1D barcodes of bm, whose origin is at at in the
+// image, searching the parts of bm around the first one found for the
+// others, as ZXing's GenericMultipleBarcodeReader does.
+func decodeLinear(bm *gozxing.BinaryBitmap, at image.Point, depth int) []Code {
+	for _, reader := range linearReaders() {
+		r, err := reader.Decode(bm, hints)
+		if err != nil {
+			continue
+		}
+		codes := add(nil, r, at)
+		if len(codes) == 0 || depth >= maxDepth {
+			return codes
+		}
+		b := codes[0].Bounds.Sub(at)
+		w, h := bm.GetWidth(), bm.GetHeight()
+		for _, part := range []image.Rectangle{
+			image.Rect(0, 0, b.Min.X, h),
+			image.Rect(b.Max.X, 0, w, h),
+			image.Rect(0, 0, w, b.Min.Y),
+			image.Rect(0, b.Max.Y, w, h),
+		} {
+			if part.Dx() < minSide || part.Dy() < minSide {
+				continue
+			}
+			crop, err := bm.Crop(part.Min.X, part.Min.Y, part.Dx(), part.Dy())
+			if err != nil {
+				continue
+			}
+			for _, c := range decodeLinear(crop, at.Add(part.Min), depth+1) {
+				if !contains(codes, c) {
+					codes = append(codes, c)
+				}
+			}
+		}
+		return codes
+	}
+	return nil
+}
+
+// add adds the code of r, found in the part of the image at at, to codes,
+// unless its format is not one of those of the package.
+func add(codes []Code, r *gozxing.Result, at image.Point) []Code {
+	f, ok := formats[r.GetBarcodeFormat()]
+	if !ok {
+		return codes
+	}
+	return append(codes, Code{Format: f, Text: r.GetText(), Bounds: bounds(r.GetResultPoints()).Add(at)})
+}
+
+// bounds returns the rectangle of the points.
+func bounds(points []gozxing.ResultPoint) image.Rectangle {
+	var r image.Rectangle
+	for i, p := range points {
+		x, y := int(math.Floor(p.GetX())), int(math.Floor(p.GetY()))
+		pr := image.Rect(x, y, x+1, y+1)
+		if i == 0 {
+			r = pr
+		} else {
+			r = r.Union(pr)
+		}
+	}
+	return r
+}
+
+// offset returns codes moved by d, the origin of the image.
+func offset(codes []Code, d image.Point) []Code {
+	for i := range codes {
+		codes[i].Bounds = codes[i].Bounds.Add(d)
+	}
+	return codes
+}
+
+func contains(codes []Code, c Code) bool {
+	for _, d := range codes {
+		if d.Format == c.Format && d.Text == c.Text {
+			return true
+		}
+	}
+	return false
+}
+
+func overlaps(codes []Code, r image.Rectangle) bool {
+	for _, c := range codes {
+		if c.Bounds.Overlaps(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package scan
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/code39"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// page returns a white image of width w and height h.
+func page(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return img
+}
+
+// place draws c on img at (x, y), each module scale pixels wide.
+func place(img draw.Image, c image.Image, x, y, scale int) {
+	b := c.Bounds()
+	for v := 0; v < b.Dy(); v++ {
+		for u := 0; u < b.Dx(); u++ {
+			col := c.At(b.Min.X+u, b.Min.Y+v)
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.Set(x+u*scale+dx, y+v*scale+dy, col)
+				}
+			}
+		}
+	}
+}
+
+// qrCode returns an image of the QR code of text, with its quiet zone.
+func qrCode(t *testing.T, text string, level qr.ErrorCorrectionLevel, scale int) *image.Gray {
+	t.Helper()
+	c, err := qr.Encode(text, level, qr.Auto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dim := c.Bounds().Dx()
+	img := page((dim+8)*scale, (dim+8)*scale)
+	place(img, c, 4*scale, 4*scale, scale)
+	return img
+}
+
+// barcode1D returns an image of the 1D barcode c, h pixels high, with its
+// quiet zone.
+func barcode1D(t *testing.T, c barcode.Barcode, scale, h int) *image.Gray {
+	t.Helper()
+	w := c.Bounds().Dx()
+	img := page((w+20)*scale, h+20)
+	for y := 10; y < 10+h; y++ {
+		for u := 0; u < w; u++ {
+			for dx := 0; dx < scale; dx++ {
+				img.Set((10+u)*scale+dx, y, c.At(u, 0))
+			}
+		}
+	}
+	return img
+}
+
+// rotate returns img rotated by angle degrees clockwise about its center, on a
+// white page large enough for it.
+func rotate(img image.Image, angle float64) *image.Gray {
+	b := img.Bounds()
+	a := angle * math.Pi / 180
+	sin, cos := math.Sin(a), math.Cos(a)
+	w := int(math.Abs(float64(b.Dx())*cos) + math.Abs(float64(b.Dy())*sin) + 1)
+	h := int(math.Abs(float64(b.Dx())*sin) + math.Abs(float64(b.Dy())*cos) + 1)
+	out := page(w, h)
+	cx, cy := float64(b.Dx())/2, float64(b.Dy())/2
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// The pixel of img that lands on (x, y).
+			dx, dy := float64(x)+0.5-float64(w)/2, float64(y)+0.5-float64(h)/2
+			u, v := dx*cos+dy*sin+cx, -dx*sin+dy*cos+cy
+			if p := image.Pt(int(math.Floor(u)), int(math.Floor(v))); p.In(b) {
+				out.Set(x, y, img.At(p.X, p.Y))
+			}
+		}
+	}
+	return out
+}
+
+func decodeOne(t *testing.T, img image.Image, format Format, text string) {
+	t.Helper()
+	codes := Decode(img)
+	if len(codes) != 1 {
+		t.Fatalf("got %d codes: %+v, want 1", len(codes), codes)
+	}
+	if c := codes[0]; c.Format != format || c.Text != text {
+		t.Errorf("got %s %q, want %s %q", c.Format, c.Text, format, text)
+	}
+}
+
+func TestDecodeQR(t *testing.T) {
+	texts := []string{
+		"0123456789",
+		"HELLO WORLD",
+		"https://example.com/?q=go-app",
+		"Ünïcödé, 日本語",
+		strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20),
+	}
+	levels := []qr.ErrorCorrectionLevel{qr.L, qr.M, qr.Q, qr.H}
+	for _, text := range texts {
+		for _, level := range levels {
+			decodeOne(t, qrCode(t, text, level, 3), QR, text)
+		}
+	}
+}
+
+func TestDecodeQRVersions(t *testing.T) {
+	// The byte mode holds 17 to 2953 bytes, from the version 1 to 40 at
+	// level L.
+	r := rand.New(rand.NewSource(1))
+	dims := map[int]bool{}
+	for n := 1; n <= 2953; n += 1 + n/24 {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		text := string(b)
+		img := qrCode(t, text, qr.L, 2)
+		dims[img.Bounds().Dx()/2-8] = true
+		decodeOne(t, img, QR, text)
+	}
+	if len(dims) < 38 {
+		t.Errorf("got %d versions, want 38 at least", len(dims))
+	}
+}
+
+func TestDecodeQRRotated(t *testing.T) {
+	text := "https://github.com/suntong/go-app-demos"
+	img := qrCode(t, text, qr.M, 4)
+	for _, angle := range []float64{90, 180, 270, 30, -15} {
+		t.Run(fmt.Sprint(angle), func(t *testing.T) {
+			decodeOne(t, rotate(img, angle), QR, text)
+		})
+	}
+}
+
+func TestDecodeQRDamaged(t *testing.T) {
+	text := "Damaged, but within the error correction"
+	img := qrCode(t, text, qr.H, 3)
+	// A blot over a few modules of data.
+	b := img.Bounds()
+	draw.Draw(img, image.Rect(b.Dx()/2, b.Dy()/2, b.Dx()/2+12, b.Dy()/2+12), image.Black, image.Point{}, draw.Src)
+	decodeOne(t, img, QR, text)
+}
+
+func TestDecodeLinear(t *testing.T) {
+	must := func(c barcode.Barcode, err error) barcode.Barcode {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	tests := []struct {
+		code   barcode.Barcode
+		format Format
+		text   string
+	}{
+		{must(ean.Encode("5901234123457")), EAN13, "5901234123457"},
+		{must(ean.Encode("0036000291452")), UPCA, "036000291452"},
+		{must(ean.Encode("96385074")), EAN8, "96385074"},
+		{must(code128.Encode("Hello, World!")), Code128, "Hello, World!"},
+		{must(code128.Encode("1234567890")), Code128, "1234567890"},
+		{must(code39.Encode("CODE-39 TEST", false, false)), Code39, "CODE-39 TEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			img := barcode1D(t, tt.code, 2, 40)
+			for _, angle := range []float64{0, 90, 180} {
+				decodeOne(t, rotate(img, angle), tt.format, tt.text)
+			}
+		})
+	}
+}
+
+func TestDecodeMany(t *testing.T) {
+	img := page(600, 400)
+	place(img, qrCode(t, "first", qr.M, 1), 20, 20, 4)
+	place(img, qrCode(t, "second", qr.Q, 1), 320, 40, 3)
+	c, err := ean.Encode("5901234123457")
+	if err != nil {
+		t.Fatal(err)
+	}
+	place(img, barcode1D(t, c, 2, 60), 100, 260, 1)
+
+	codes := Decode(img)
+	var got []string
+	for _, c := range codes {
+		got = append(got, fmt.Sprintf("%s %s", c.Format, c.Text))
+	}
+	want := []string{"QR Code first", "QR Code second", "EAN-13 5901234123457"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if b := codes[0].Bounds; !b.Overlaps(image.Rect(20, 20, 160, 160)) {
+		t.Errorf("got bounds %v, want about (36,36)-(120,120)", b)
+	}
+}
+
+func TestDecodeManyBarcodes(t *testing.T) {
+	img := page(500, 300)
+	for _, b := range []struct {
+		text string
+		x, y int
+	}{
+		{"5901234123457", 20, 10},
+		// The same barcode again.
+		{"5901234123457", 260, 10},
+		{"96385074", 20, 100},
+	} {
+		c, err := ean.Encode(b.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		place(img, barcode1D(t, c, 2, 60), b.x, b.y, 1)
+	}
+	c, err := code128.Encode("Code 128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	place(img, barcode1D(t, c, 1, 60), 20, 190, 1)
+
+	var got []string
+	for _, c := range Decode(img) {
+		got = append(got, fmt.Sprintf("%s %s", c.Format, c.Text))
+	}
+	want := []string{"EAN-13 5901234123457", "EAN-8 96385074", "Code 128 Code 128"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDecodeNothing(t *testing.T) {
+	img := page(200, 100)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		x, y := r.Intn(200), r.Intn(100)
+		draw.Draw(img, image.Rect(x, y, x+r.Intn(8), y+r.Intn(8)), image.NewUniform(color.Gray{uint8(r.Intn(256))}), image.Point{}, draw.Src)
+	}
+	if codes := Decode(img); len(codes) != 0 {
+		t.Errorf("got %+v, want none", codes)
+	}
+}
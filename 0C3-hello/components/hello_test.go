@@ -0,0 +1,51 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// helloParent renders a HelloUI the way Hello does in the web browser, where
+// Hello does not only say the app is loading.
+type helloParent struct {
+	app.Compo
+
+	// rendered are the HelloUI of every rendering, the first one being the
+	// mounted one.
+	rendered []*HelloUI
+}
+
+func (p *helloParent) Render() app.UI {
+	ui := &HelloUI{}
+	p.rendered = append(p.rendered, ui)
+	return app.Div().Body(ui)
+}
+
+func TestHelloUI(t *testing.T) {
+	p := &helloParent{}
+	d := app.NewServerTester(p)
+	defer d.Close()
+	d.Consume()
+	if html := app.HTMLString(p); !strings.Contains(html, `placeholder="What is your name?"`) {
+		t.Errorf("no name input:\n%s", html)
+	}
+
+	// The name typed, as OnChange sets it.
+	ui := p.rendered[0]
+	ui.name = "Gopher"
+	ui.Update()
+	d.Consume()
+	if html := app.HTMLString(p); !strings.Contains(html, "Gopher") || strings.Contains(html, "<input") {
+		t.Errorf("no greeting:\n%s", html)
+	}
+
+	// Hello renders a new HelloUI on every update: the mounted one keeps
+	// the name typed.
+	p.Update()
+	d.Consume()
+	if html := app.HTMLString(p); !strings.Contains(html, "Gopher") {
+		t.Errorf("name lost once Hello updates:\n%s", html)
+	}
+}
@@ -0,0 +1,41 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+func TestLoginForm(t *testing.T) {
+	for _, tc := range []struct {
+		kind     FormKind
+		title    string
+		inputs   []string
+		excludes []string
+	}{
+		{Login, "Login", []string{"username", "password"}, []string{"confirm-password"}},
+		{Register, "Register", []string{"username", "password", "confirm-password"}, nil},
+		{Recover, "Recover", []string{"username"}, []string{"password", "confirm-password"}},
+	} {
+		form := NewLoginForm(tc.kind)
+		d := app.NewServerTester(form)
+		d.Consume()
+		html := app.HTMLString(form)
+		d.Close()
+
+		if !strings.Contains(html, ">"+tc.title+"</h4>") {
+			t.Errorf("%s form without its title:\n%s", tc.title, html)
+		}
+		for _, name := range tc.inputs {
+			if !strings.Contains(html, `name="`+name+`"`) {
+				t.Errorf("%s form without the %s input", tc.title, name)
+			}
+		}
+		for _, name := range tc.excludes {
+			if strings.Contains(html, `name="`+name+`"`) {
+				t.Errorf("%s form with the %s input", tc.title, name)
+			}
+		}
+	}
+}
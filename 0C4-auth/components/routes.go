@@ -0,0 +1,11 @@
+package components
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &Hello{}},
+	{Path: "/l", Compo: &LoginForm{}},
+	{Path: "/l2", Compo: NewLoginForm(Register)},
+	{Path: "/l3", Compo: NewLoginForm(Recover)},
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{
+			Path:     "/",
+			Contains: []string{"World!", `placeholder="What is your name?"`},
+		},
+		{
+			Path:     "/l",
+			Contains: []string{">Login</h4>", `name="username"`, `name="password"`},
+			Excludes: []string{`name="confirm-password"`},
+		},
+		// app.Route mounts a new LoginForm, whose kind is Login: /l2 and
+		// /l3 show the login form instead of the register and recover ones,
+		// which is why the demo is not working.
+		{
+			Path:     "/l2",
+			Contains: []string{">Login</h4>", `name="username"`, `name="password"`},
+			Excludes: []string{">Register</h4>", `name="confirm-password"`},
+		},
+		{
+			Path:     "/l3",
+			Contains: []string{">Login</h4>", `name="username"`, `name="password"`},
+			Excludes: []string{">Recover</h4>"},
+		},
+	})
+}
@@ -0,0 +1,9 @@
+package components
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// Routes associates the demo components with their paths.
+var Routes = []server.Route{
+	{Path: "/", Compo: &appControl{}},
+	{Path: "/appControl", Compo: &appControl{}},
+}
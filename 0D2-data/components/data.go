@@ -0,0 +1,50 @@
+package components
+
+import (
+	"log"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+const dataStorageKey = "0D2-data.Name"
+
+// appControl is a component that displays a simple "Hello World!". A component is a
+// customizable, independent, and reusable UI element. It is created by
+// embedding app.Compo into a struct.
+type appControl struct {
+	app.Compo
+	name string
+}
+
+// The Render method is where the component appearance is defined. Here, a
+// "Hello World!" is displayed as a heading.
+func (uc *appControl) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Body(
+			app.Text("Hello, "),
+			app.If(uc.name != "",
+				app.Text(uc.name),
+			).Else(
+				app.Text("World!"),
+			),
+		),
+		app.P().Body(
+			app.Input().
+				Type("text").
+				Value(uc.name).
+				Placeholder("What is your name?").
+				AutoFocus(true).
+				//OnChange(uc.ValueTo(&uc.name)),
+				OnChange(uc.OnChange),
+		),
+	)
+}
+
+func (uc *appControl) OnChange(ctx app.Context, e app.Event) {
+	uc.name = ctx.JSSrc().Get("value").String()
+	//app.Window().Get("localStorage").Call("setItem", dataStorageKey, uc.name)
+	ctx.LocalStorage().Set(dataStorageKey, uc.name)
+	uc.name = ""
+	ctx.LocalStorage().Get(dataStorageKey, &uc.name)
+	log.Println("readFromLocalStorage:", uc.name)
+}
@@ -0,0 +1,13 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	servertest.Run(t, Routes, []servertest.Page{
+		{Path: "/", Contains: []string{"World!", `placeholder="What is your name?"`}},
+	})
+}
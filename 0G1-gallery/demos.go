@@ -0,0 +1,29 @@
+// Code generated by gen.go from README.md; DO NOT EDIT.
+
+package main
+
+var demos = []demo{
+	{Name: "0A1-hello", Description: "from `README.md`, the most basic one", Working: true},
+	{Name: "0A2-hello", Description: "showcasing `app.If`", Working: true},
+	{Name: "0A2A-hello", Description: "autocomplete test cases", Working: true},
+	{Name: "0A2C-hello", Description: "using capital (exported) fields", Working: true},
+	{Name: "0A3-hello", Description: "adds lifecycle events custom actions & logging", Working: true},
+	{Name: "0B1-textarea", Description: "text area demo", Working: true},
+	{Name: "0B2-codecopy", Description: "codecopy from text area, the Go port of a plain HTML/JS page, with DOM tests", Working: true},
+	{Name: "0B2A-codecopy", Description: "working copy from text area demo, copying the highlighted code as HTML, and as Markdown, with the code streamed by the server", Working: true},
+	{Name: "0B2C-codecopy", Description: "fix copy from text area using window.navigator.clipboard, now through `pkg/clipboard`", Working: true},
+	{Name: "0B2D-codecopy", Description: "paste-bin of code snippets, shared at short links until they expire", Working: true},
+	{Name: "0B3A-textarea", Description: "paste image to text area, turned into black and white in Go, then into a Factorio blueprint; its metadata is shown and stripped, and the image uploaded to a gallery", Working: true},
+	{Name: "0B3B-textarea", Description: "paste or drop text, HTML and images onto the text area; the images are uploaded to a gallery and scanned for QR codes and barcodes", Working: true},
+	{Name: "0C1-hello", Description: "duplicated from my go-app-hello, using components", Working: true},
+	{Name: "0C2-hello", Description: "add button component, showcasing modularized building", Working: true},
+	{Name: "0C3-hello", Description: "two-level components, the 1st level is universal", Working: true},
+	{Name: "0C3C-hello", Description: "`0C3-hello` with capital fields, not working", Working: false},
+	{Name: "0C3D-hello", Description: "`0C3-hello` with capital fields under private struct, OK", Working: true},
+	{Name: "0C4-auth", Description: "login demo; not working", Working: false},
+	{Name: "0D1-data", Description: "showcase localStorage access, via JS", Working: true},
+	{Name: "0D2-data", Description: "showcase localStorage access, go-app wrapped", Working: true},
+	{Name: "0L1-hello", Description: "tried for AWS Lambda, not working on AWS yet; `go test` invokes it with recorded events", Working: false},
+	{Name: "0M1-data", Description: "menu", Working: true},
+	{Name: "0S1-hello", Description: "tried for Space, not working", Working: false},
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/server"
+
+	a1 "github.com/suntong/go-app-demos/0A1-hello/components"
+	a2 "github.com/suntong/go-app-demos/0A2-hello/components"
+	a2a "github.com/suntong/go-app-demos/0A2A-hello/components"
+	a2c "github.com/suntong/go-app-demos/0A2C-hello/components"
+	a3 "github.com/suntong/go-app-demos/0A3-hello/components"
+	b1 "github.com/suntong/go-app-demos/0B1-textarea/components"
+	b2 "github.com/suntong/go-app-demos/0B2-codecopy/components"
+	b2a "github.com/suntong/go-app-demos/0B2A-codecopy/components"
+	b2c "github.com/suntong/go-app-demos/0B2C-codecopy/components"
+	b3a "github.com/suntong/go-app-demos/0B3A-textarea/components"
+	b3b "github.com/suntong/go-app-demos/0B3B-textarea/components"
+	c1 "github.com/suntong/go-app-demos/0C1-hello/components"
+	c2 "github.com/suntong/go-app-demos/0C2-hello/components"
+	c3 "github.com/suntong/go-app-demos/0C3-hello/components"
+	c3c "github.com/suntong/go-app-demos/0C3C-hello/components"
+	c3d "github.com/suntong/go-app-demos/0C3D-hello/components"
+	c4 "github.com/suntong/go-app-demos/0C4-auth/components"
+	d1 "github.com/suntong/go-app-demos/0D1-data/components"
+	d2 "github.com/suntong/go-app-demos/0D2-data/components"
+	l1 "github.com/suntong/go-app-demos/0L1-hello/components"
+	m1 "github.com/suntong/go-app-demos/0M1-data/components"
+	s1 "github.com/suntong/go-app-demos/0S1-hello/components"
+)
+
+// demo is an entry of the README demo list.
+type demo struct {
+	Name        string
+	Description string
+	Working     bool
+}
+
+// mounts holds the routes of every demo in the gallery, by demo name. Demos
+// that do not build, or that need a database, such as 0B2D-codecopy, are
+// listed on the index page but not mounted.
+var mounts = map[string][]server.Route{
+	"0A1-hello":     a1.Routes,
+	"0A2-hello":     a2.Routes,
+	"0A2A-hello":    a2a.Routes,
+	"0A2C-hello":    a2c.Routes,
+	"0A3-hello":     a3.Routes,
+	"0B1-textarea":  b1.Routes,
+	"0B2-codecopy":  b2.Routes,
+	"0B2A-codecopy": b2a.Routes,
+	"0B2C-codecopy": b2c.Routes,
+	"0B3A-textarea": b3a.Routes,
+	"0B3B-textarea": b3b.Routes,
+	"0C1-hello":     c1.Routes,
+	"0C2-hello":     c2.Routes,
+	"0C3-hello":     c3.Routes,
+	"0C3C-hello":    c3c.Routes,
+	"0C3D-hello":    c3d.Routes,
+	"0C4-auth":      c4.Routes,
+	"0D1-data":      d1.Routes,
+	"0D2-data":      d2.Routes,
+	"0L1-hello":     l1.Routes,
+	"0M1-data":      m1.Routes,
+	"0S1-hello":     s1.Routes,
+}
+
+// endpoints holds the server endpoints of the demos, by demo name. They are
+// served on their own paths, which the demos call whatever their prefix.
+// 0B3A-textarea and 0B3B-textarea have none here: the gallery keeps no
+// upload store, so their images are pasted but not saved.
+var endpoints = map[string][]server.Endpoint{
+	"0B2A-codecopy": b2a.Endpoints,
+}
+
+// prefix returns the path prefix a demo is mounted under.
+func prefix(name string) string {
+	return "/" + name + "/"
+}
+
+// routes returns the index page route followed by the routes of every
+// mounted demo, each under its own path prefix.
+func routes() []server.Route {
+	rs := []server.Route{{Path: "/", Compo: &index{}}}
+	for _, d := range demos {
+		for _, r := range mounts[d.Name] {
+			path := prefix(d.Name) + strings.TrimPrefix(r.Path, "/")
+			rs = append(rs, server.Route{Path: path, Compo: r.Compo})
+		}
+	}
+	return rs
+}
+
+// allEndpoints returns the endpoints of every mounted demo, along with the
+// redirection of its prefix without the trailing slash, such as /0A1-hello,
+// to its index page: the relative links of the demos only resolve under the
+// prefix.
+func allEndpoints() []server.Endpoint {
+	var es []server.Endpoint
+	for _, d := range demos {
+		if mounts[d.Name] == nil {
+			continue
+		}
+		es = append(es, server.Endpoint{
+			Pattern: strings.TrimSuffix(prefix(d.Name), "/"),
+			Handler: http.RedirectHandler(prefix(d.Name), http.StatusMovedPermanently),
+		})
+		es = append(es, endpoints[d.Name]...)
+	}
+	return es
+}
+
+// index is the gallery index page, listing every demo of the README along
+// with its status.
+type index struct {
+	app.Compo
+}
+
+func (i *index) Render() app.UI {
+	return app.Div().Body(
+		app.H1().Text("go-app demos"),
+		app.Ul().Body(
+			app.Range(demos).Slice(func(n int) app.UI {
+				d := demos[n]
+				return app.Li().Body(
+					app.If(mounts[d.Name] != nil,
+						app.A().Href(prefix(d.Name)).Text(d.Name),
+					).Else(
+						app.Span().Text(d.Name),
+					),
+					app.Text(": "+d.Description+" "),
+					status(d),
+				)
+			}),
+		),
+	)
+}
+
+func status(d demo) app.UI {
+	switch {
+	case mounts[d.Name] == nil:
+		return app.Em().Class("status").Text("(not in gallery)")
+	case d.Working:
+		return app.Em().Class("status").Text("(working)")
+	default:
+		return app.Em().Class("status").Text("(not working)")
+	}
+}
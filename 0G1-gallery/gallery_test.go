@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server/servertest"
+)
+
+func TestRoutes(t *testing.T) {
+	index := servertest.Page{Path: "/"}
+	for _, d := range demos {
+		index.Contains = append(index.Contains, d.Name)
+	}
+	servertest.Run(t, routes(), []servertest.Page{
+		index,
+		{Path: "/0A1-hello/", Contains: []string{"<h1>Hello World!</h1>"}},
+		{Path: "/0A3-hello/hello", Contains: []string{"World!"}},
+		{Path: "/0C4-auth/l2", Contains: []string{">Login</h4>"}},
+	})
+}
+
+func TestRedirects(t *testing.T) {
+	var h http.Handler
+	for _, e := range allEndpoints() {
+		if e.Pattern == "/0A1-hello" {
+			h = e.Handler
+		}
+	}
+	if h == nil {
+		t.Fatal("no redirection of /0A1-hello")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/0A1-hello", nil))
+	if loc := w.Header().Get("Location"); w.Code != http.StatusMovedPermanently || loc != "/0A1-hello/" {
+		t.Errorf("/0A1-hello: %d to %q, want %d to /0A1-hello/", w.Code, loc, http.StatusMovedPermanently)
+	}
+}
+
+func TestMounts(t *testing.T) {
+	listed := make(map[string]bool)
+	for _, d := range demos {
+		listed[d.Name] = true
+	}
+	for name := range mounts {
+		if !listed[name] {
+			t.Errorf("%s is mounted but not listed in README.md", name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+//go:build ignore
+
+// gen.go generates demos.go from the demo list of the top-level README.md.
+// Run it with go generate.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// demoLine matches the README entries, each a list item starting with the
+// demo name in bold, such as "**0A1-hello**: the most basic one".
+var demoLine = regexp.MustCompile(`^- \*\*([0-9A-Za-z-]+)\*\*: (.*)$`)
+
+func main() {
+	f, err := os.Open("../README.md")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by gen.go from README.md; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package main")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "var demos = []demo{")
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "## ") {
+			// The demo list ends with the first section.
+			break
+		}
+		m := demoLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "\t{Name: %q, Description: %q, Working: %t},\n",
+			m[1], m[2], !strings.Contains(m[2], "not working"))
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintln(&b, "}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("demos.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,73 @@
+module github.com/suntong/go-app-demos/0G1-gallery
+
+go 1.21
+
+require (
+	github.com/maxence-charriere/go-app/v9 v9.8.0
+	github.com/suntong/go-app-demos/0A1-hello v0.0.0
+	github.com/suntong/go-app-demos/0A2-hello v0.0.0
+	github.com/suntong/go-app-demos/0A2A-hello v0.0.0
+	github.com/suntong/go-app-demos/0A2C-hello v0.0.0
+	github.com/suntong/go-app-demos/0A3-hello v0.0.0
+	github.com/suntong/go-app-demos/0B1-textarea v0.0.0
+	github.com/suntong/go-app-demos/0B2-codecopy v0.0.0
+	github.com/suntong/go-app-demos/0B2A-codecopy v0.0.0
+	github.com/suntong/go-app-demos/0B2C-codecopy v0.0.0
+	github.com/suntong/go-app-demos/0B3A-textarea v0.0.0
+	github.com/suntong/go-app-demos/0B3B-textarea v0.0.0
+	github.com/suntong/go-app-demos/0C1-hello v0.0.0
+	github.com/suntong/go-app-demos/0C2-hello v0.0.0
+	github.com/suntong/go-app-demos/0C3-hello v0.0.0
+	github.com/suntong/go-app-demos/0C3C-hello v0.0.0
+	github.com/suntong/go-app-demos/0C3D-hello v0.0.0
+	github.com/suntong/go-app-demos/0C4-auth v0.0.0
+	github.com/suntong/go-app-demos/0D1-data v0.0.0
+	github.com/suntong/go-app-demos/0D2-data v0.0.0
+	github.com/suntong/go-app-demos/0L1-hello v0.0.0
+	github.com/suntong/go-app-demos/0M1-data v0.0.0
+	github.com/suntong/go-app-demos/0S1-hello v0.0.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
+)
+
+require (
+	github.com/akrylysov/algnhsa v1.0.0 // indirect
+	github.com/alecthomas/chroma/v2 v2.14.0 // indirect
+	github.com/aws/aws-lambda-go v1.37.0 // indirect
+	github.com/boombuler/barcode v1.1.0 // indirect
+	github.com/deckarep/golang-set v1.7.1 // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/makiuchi-d/gozxing v0.1.1 // indirect
+	github.com/mlctrez/edgeefy v0.0.0-20210214182222-402531e31b4f // indirect
+	github.com/mlctrez/imgtofactbp v1.0.0 // indirect
+	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646 // indirect
+	golang.org/x/text v0.3.7 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+	gonum.org/v1/gonum v0.9.3 // indirect
+)
+
+replace (
+	github.com/suntong/go-app-demos/0A1-hello => ../0A1-hello
+	github.com/suntong/go-app-demos/0A2-hello => ../0A2-hello
+	github.com/suntong/go-app-demos/0A2A-hello => ../0A2A-hello
+	github.com/suntong/go-app-demos/0A2C-hello => ../0A2C-hello
+	github.com/suntong/go-app-demos/0A3-hello => ../0A3-hello
+	github.com/suntong/go-app-demos/0B1-textarea => ../0B1-textarea
+	github.com/suntong/go-app-demos/0B2-codecopy => ../0B2-codecopy
+	github.com/suntong/go-app-demos/0B2A-codecopy => ../0B2A-codecopy
+	github.com/suntong/go-app-demos/0B2C-codecopy => ../0B2C-codecopy
+	github.com/suntong/go-app-demos/0B3A-textarea => ../0B3A-textarea
+	github.com/suntong/go-app-demos/0B3B-textarea => ../0B3B-textarea
+	github.com/suntong/go-app-demos/0C1-hello => ../0C1-hello
+	github.com/suntong/go-app-demos/0C2-hello => ../0C2-hello
+	github.com/suntong/go-app-demos/0C3-hello => ../0C3-hello
+	github.com/suntong/go-app-demos/0C3C-hello => ../0C3C-hello
+	github.com/suntong/go-app-demos/0C3D-hello => ../0C3D-hello
+	github.com/suntong/go-app-demos/0C4-auth => ../0C4-auth
+	github.com/suntong/go-app-demos/0D1-data => ../0D1-data
+	github.com/suntong/go-app-demos/0D2-data => ../0D2-data
+	github.com/suntong/go-app-demos/0L1-hello => ../0L1-hello
+	github.com/suntong/go-app-demos/0M1-data => ../0M1-data
+	github.com/suntong/go-app-demos/0S1-hello => ../0S1-hello
+	github.com/suntong/go-app-demos/pkg => ../pkg
+)
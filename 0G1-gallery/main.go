@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/fs"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/codeblock"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+//go:generate go run gen.go
+
+// web holds the web directory the resources are served from: the one of the
+// working directory, unless built with -tags embed (see embed.go).
+var web fs.FS
+
+// The main function is the entry point where the app is configured and started.
+// It is executed in 2 different environments: A client (the web browser) and a
+// server.
+func main() {
+	// Every demo is mounted under its own path prefix, such as
+	// /0B2A-codecopy/, next to the index page served on /.
+	server.Run(config(web))
+}
+
+// config returns the configuration of the app, with its resources served
+// from the web directory of web.
+func config(web fs.FS) server.Config {
+	return server.Config{
+		Routes:    routes(),
+		Endpoints: allEndpoints(),
+		Handler: &app.Handler{
+			Name:        "go-app demos",
+			Description: "Every go-app demo in a single app",
+			// The style sheet of the codecopy demos, which the others
+			// do without.
+			Styles: []string{"/web/styles.css"},
+			Icon: app.Icon{
+				Default:    "/web/copy-icon.png",
+				Large:      "/web/copy-icon.png",
+				AppleTouch: "/web/copy-icon.png",
+			},
+			// The style sheet of the highlighted code blocks.
+			RawHeaders: []string{codeblock.StyleSheet("github")},
+		},
+		Web: web,
+	}
+}
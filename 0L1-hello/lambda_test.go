@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/server"
+	"github.com/suntong/go-app-demos/pkg/server/lambdatest"
+)
+
+// TestLambda serves the demo through algnhsa, as AWS Lambda does, and invokes
+// it with the API Gateway v1, API Gateway v2 and ALB events recorded in
+// testdata.
+func TestLambda(t *testing.T) {
+	// Not a real app.wasm, but binary content, which must survive the base64
+	// encoding. The brotli variant is served to the browsers accepting it.
+	dir := t.TempDir()
+	wasm := []byte("\x00asm\x01\x00\x00\x00\xff\xfe")
+	wasmBr := []byte("\x1b\x09\x00\xf8\xff")
+	write(t, filepath.Join(dir, "web", "app.wasm"), wasm)
+	write(t, filepath.Join(dir, "web", "app.wasm.br"), wasmBr)
+	h := server.NewHandler(config(os.DirFS(dir)))
+
+	tests := []struct {
+		path        string
+		contentType string
+		encoding    string
+		contains    []byte
+		equals      []byte
+	}{
+		{path: "/", contentType: "text/html", contains: []byte("World!")},
+		{path: "/app.js", contentType: "application/javascript", contains: []byte("/web/app.wasm?v=")},
+		{path: "/manifest.webmanifest", contentType: "application/manifest+json", contains: []byte(`"start_url"`)},
+		{path: "/web/app.wasm", contentType: "application/wasm", encoding: "br", equals: wasmBr},
+	}
+	for _, fixture := range []string{"apigw-v1", "apigw-v2", "alb"} {
+		event, err := os.ReadFile(filepath.Join("testdata", fixture+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, test := range tests {
+			t.Run(fixture+test.path, func(t *testing.T) {
+				res := invoke(t, h, event, test.path)
+
+				if res.StatusCode != 200 {
+					t.Errorf("status = %d, want 200", res.StatusCode)
+				}
+				if !res.IsBase64Encoded {
+					t.Error("body is not base64 encoded")
+				}
+				if ct := res.Header("Content-Type"); !strings.HasPrefix(ct, test.contentType) {
+					t.Errorf("Content-Type = %q, want %s", ct, test.contentType)
+				}
+				if enc := res.Header("Content-Encoding"); enc != test.encoding {
+					t.Errorf("Content-Encoding = %q, want %q", enc, test.encoding)
+				}
+
+				body, err := res.Content()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if test.contains != nil && !bytes.Contains(body, test.contains) {
+					t.Errorf("body does not contain %q:\n%s", test.contains, body)
+				}
+				if test.equals != nil && !bytes.Equal(body, test.equals) {
+					t.Errorf("body = %q, want %q", body, test.equals)
+				}
+			})
+		}
+	}
+}
+
+func invoke(t *testing.T, h http.Handler, event []byte, path string) lambdatest.Response {
+	t.Helper()
+	event, err := lambdatest.WithPath(event, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := lambdatest.Invoke(h, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := lambdatest.ParseResponse(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func write(t *testing.T, name string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
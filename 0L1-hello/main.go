@@ -1,42 +1,54 @@
+// Command 0L1-hello is the Hello World! demo, deployed to AWS Lambda: executed
+// by Lambda, it serves the API Gateway v1, API Gateway v2 and ALB events
+// through algnhsa (see pkg/server).
+//
+// Its test serves it in-process the same way (see pkg/server/lambdatest), and
+// invokes it with the events recorded in testdata.
+//
+// Lambda responses are limited to 6 MB, so app.wasm has to be served from its
+// brotli variant, which the mage build writes next to it:
+//
+//	go run mage.go build 0L1-hello
 package main
 
 import (
-	"net/http"
+	"io/fs"
 
-	"project/components"
+	"github.com/suntong/go-app-demos/0L1-hello/components"
 
-	"github.com/akrylysov/algnhsa"
 	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/server"
 )
 
+// web holds the web directory the resources are served from: the one of the
+// working directory, unless built with -tags embed (see embed.go).
+var web fs.FS
+
 // The main function is the entry point where the app is configured and started.
 // It is executed in 2 different environments: A client (the web browser) and a
 // server.
 func main() {
-	// The first thing to do is to associate the hello component with a path.
+	// server.Run associates the components with their paths, on both client
+	// and server-side, and launches the app when executed in the web browser.
 	//
-	// This is done by calling the Route() function,  which tells go-app what
-	// component to display for a given path, on both client and server-side.
-	app.Route("/", &components.Hello{})
-
-	// Once the routes set up, the next thing to do is to either launch the app
-	// or the server that serves the app.
+	// On the server-side, it serves the app with the Handler, an HTTP handler
+	// that serves the client and all its required resources to make it work
+	// into a web browser.
 	//
-	// When executed on the server-side, RunWhenOnBrowser() does nothing, which
-	// lets room for server implementation without the need for precompiling
-	// instructions.
-	app.RunWhenOnBrowser()
-
-	// Finally, launching the server that serves the app is done by using the Go
-	// standard HTTP package.
-	//
-	// The Handler is an HTTP handler that serves the client and all its
-	// required resources to make it work into a web browser. Here it is
-	// configured to handle requests with a path that starts with "/".
-	http.Handle("/", &app.Handler{
-		Name:        "Hello",
-		Description: "An Hello World! example",
-	})
+	// Executed by AWS Lambda, it serves the Lambda events through algnhsa
+	// instead of listening.
+	server.Run(config(web))
+}
 
-	algnhsa.ListenAndServe(http.DefaultServeMux, nil)
+// config returns the configuration of the app, with its resources served
+// from the web directory of web.
+func config(web fs.FS) server.Config {
+	return server.Config{
+		Routes: components.Routes,
+		Handler: &app.Handler{
+			Name:        "Hello",
+			Description: "An Hello World! example",
+		},
+		Web: web,
+	}
 }
@@ -0,0 +1,15 @@
+//go:build embed
+
+package main
+
+import "embed"
+
+// Built with -tags embed, the binary carries the web directory, app.wasm
+// included, and runs from any directory.
+//
+//go:embed web
+var embedded embed.FS
+
+func init() {
+	web = embedded
+}
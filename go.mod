@@ -0,0 +1,11 @@
+module github.com/suntong/go-app-demos
+
+go 1.21
+
+require (
+	github.com/andybalholm/brotli v1.1.0
+	github.com/magefile/mage v1.15.0
+	github.com/suntong/go-app-demos/pkg v0.0.0
+)
+
+replace github.com/suntong/go-app-demos/pkg => ./pkg
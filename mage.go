@@ -1,10 +1,12 @@
+//go:build ignore
 // +build ignore
 
 package main
 
 import (
-    "os"
-    "github.com/magefile/mage/mage"
+	"os"
+
+	"github.com/magefile/mage/mage"
 )
 
 func main() { os.Exit(mage.Main()) }
@@ -0,0 +1,436 @@
+//go:build mage
+
+// Builds the go-app demos. Targets take a demo directory, such as 0A1-hello,
+// or "all" for every demo, e.g. "go run mage.go build all". Builds only happen
+// when the demo sources, or those of the local modules it replaces, changed.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/andybalholm/brotli"
+	"github.com/magefile/mage/sh"
+	"github.com/magefile/mage/target"
+	"github.com/suntong/go-app-demos/pkg/deploy"
+	"github.com/suntong/go-app-demos/pkg/devserver"
+)
+
+const goCompiler = "go"
+
+const wasmFile = "web/app.wasm"
+
+// localReplace matches the go.mod replace directives pointing to a local
+// module, such as: github.com/suntong/go-app-demos/pkg => ../pkg
+var localReplace = regexp.MustCompile(`=>\s*\.\./(\S+)`)
+
+// demos returns the demo directories matching name, "all" matching every demo.
+// A demo directory is a top-level directory with a go.mod and a main.go.
+func demos(name string) ([]string, error) {
+	if name != "all" {
+		if !isDemo(name) {
+			return nil, fmt.Errorf("%s is not a demo directory", name)
+		}
+		return []string{name}, nil
+	}
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	var ds []string
+	for _, e := range entries {
+		if e.IsDir() && isDemo(e.Name()) {
+			ds = append(ds, e.Name())
+		}
+	}
+	return ds, nil
+}
+
+func isDemo(dir string) bool {
+	for _, f := range []string{"go.mod", "main.go"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// appExecutable returns the server binary of a demo.
+func appExecutable(demo string) string {
+	exe := filepath.Join(demo, "bin", demo)
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	return exe
+}
+
+// appSources returns the source files of a demo, including those of the local
+// modules its go.mod replaces: their Go files, whatever their depth, go.mod,
+// go.sum and the web directory, which -tags embed builds into the server,
+// app.wasm and its variants excepted.
+func appSources(demo string) ([]string, error) {
+	dirs := []string{demo}
+	mod, err := os.ReadFile(filepath.Join(demo, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range localReplace.FindAllSubmatch(mod, -1) {
+		dirs = append(dirs, string(m[1]))
+	}
+
+	var sources []string
+	for _, d := range dirs {
+		built := map[string]bool{}
+		for _, f := range []string{wasmFile, wasmFile + ".br", wasmFile + ".gz"} {
+			built[filepath.Join(d, f)] = true
+		}
+		web := filepath.Join(d, "web") + string(filepath.Separator)
+		err := filepath.WalkDir(d, func(path string, e fs.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				return err
+			case e.IsDir():
+				if path != d && (e.Name() == "bin" || strings.HasPrefix(e.Name(), ".")) {
+					return filepath.SkipDir
+				}
+			case built[path]:
+			case strings.HasSuffix(path, ".go"), e.Name() == "go.mod", e.Name() == "go.sum",
+				strings.HasPrefix(path, web):
+				sources = append(sources, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+func buildWasm(demo string) error {
+	sources, err := appSources(demo)
+	if err != nil {
+		return err
+	}
+	changes, err := target.Path(filepath.Join(demo, wasmFile), sources...)
+	if err != nil || !changes {
+		return err
+	}
+	fmt.Printf("> Building %s WASM...\n", demo)
+	if err := sh.RunWithV(map[string]string{"GOOS": "js", "GOARCH": "wasm"},
+		goCompiler, "-C", demo, "build", "-o", wasmFile, "."); err != nil {
+		return err
+	}
+	return precompress(filepath.Join(demo, wasmFile))
+}
+
+// precompress writes the brotli and gzip variants of a file next to it, which
+// the demo servers serve to the browsers accepting them.
+func precompress(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	variants := []struct {
+		ext string
+		new func(io.Writer) io.WriteCloser
+	}{
+		{".br", func(w io.Writer) io.WriteCloser {
+			// BestCompression saves another tenth of the size, but takes
+			// minutes on a 20 MB app.wasm instead of seconds.
+			return brotli.NewWriterLevel(w, 9)
+		}},
+		{".gz", func(w io.Writer) io.WriteCloser {
+			gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+			return gz
+		}},
+	}
+	for _, v := range variants {
+		var buf bytes.Buffer
+		w := v.new(&buf)
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path+v.ext, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildApp(demo string) error {
+	sources, err := appSources(demo)
+	if err != nil {
+		return err
+	}
+	exe := appExecutable(demo)
+	changes, err := target.Path(exe, sources...)
+	if err != nil || !changes {
+		return err
+	}
+	fmt.Printf("> Building %s App...\n", demo)
+	return sh.RunV(goCompiler, "-C", demo, "build", "-o", filepath.Join("bin", filepath.Base(exe)), ".")
+}
+
+// forEach runs fn on every demo matching name, and reports the demos it
+// failed for.
+func forEach(name string, fn func(demo string) error) error {
+	ds, err := demos(name)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, d := range ds {
+		if err := fn(d); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", d, err)
+			failed = append(failed, d)
+		}
+	}
+	if len(failed) != 0 {
+		return fmt.Errorf("failed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// List prints the demo directories.
+func List() error {
+	ds, err := demos("all")
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.Join(ds, "\n"))
+	return nil
+}
+
+// BuildWasm builds web/app.wasm of a demo, or of all of them.
+func BuildWasm(demo string) error {
+	return forEach(demo, buildWasm)
+}
+
+// Build builds web/app.wasm and the server binary of a demo, or of all of
+// them.
+func Build(demo string) error {
+	return forEach(demo, func(d string) error {
+		if err := buildWasm(d); err != nil {
+			return err
+		}
+		return buildApp(d)
+	})
+}
+
+// Embed builds web/app.wasm and the server binary of a demo, or of all of
+// them, with the web directory embedded: the binary runs from any directory.
+func Embed(demo string) error {
+	return forEach(demo, func(d string) error {
+		if err := buildEmbeddedWasm(d); err != nil {
+			return err
+		}
+		fmt.Printf("> Building %s App with web/ embedded...\n", d)
+		return sh.RunV(goCompiler, "-C", d, "build", "-tags", "embed",
+			"-o", filepath.Join("bin", filepath.Base(appExecutable(d))), ".")
+	})
+}
+
+// buildEmbeddedWasm builds web/app.wasm of a demo along with its precompressed
+// variants, which are embedded as well, so they must be up to date.
+func buildEmbeddedWasm(demo string) error {
+	if err := buildWasm(demo); err != nil {
+		return err
+	}
+	wasm := filepath.Join(demo, wasmFile)
+	for _, ext := range []string{".br", ".gz"} {
+		stale, err := target.Path(wasm+ext, wasm)
+		if err != nil {
+			return err
+		}
+		if stale {
+			return precompress(wasm)
+		}
+	}
+	return nil
+}
+
+// Deploy generates the deployment artifacts of a demo, or of all the demos
+// with a deploy.json, from that config: a Spacefile, a Dockerfile, a systemd
+// unit and a Lambda zip. It builds the binaries they run, with web/ embedded,
+// and checks the artifacts against them.
+func Deploy(demo string) error {
+	return forEach(demo, func(d string) error {
+		_, err := os.Stat(filepath.Join(d, deploy.ConfigFile))
+		if demo == "all" && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		dm, err := deploy.LoadDemo(".", d)
+		if err != nil {
+			return err
+		}
+		if err := buildEmbeddedWasm(d); err != nil {
+			return err
+		}
+
+		fmt.Printf("> Building %s for Space and systemd...\n", d)
+		if err := buildLinux(d, "amd64", "embed", dm.Binary); err != nil {
+			return err
+		}
+		fmt.Printf("> Building %s for Lambda...\n", d)
+		bootstrap := filepath.Join("bin", "lambda", deploy.LambdaBootstrap)
+		if err := buildLinux(d, dm.Lambda.Arch, "embed,lambda.norpc", bootstrap); err != nil {
+			return err
+		}
+
+		fmt.Printf("> Writing %s deployment artifacts...\n", d)
+		if err := deploy.Write(".", dm, filepath.Join(d, bootstrap)); err != nil {
+			return err
+		}
+		return deploy.Check(".", dm)
+	})
+}
+
+// buildLinux builds the server binary of a demo for linux and arch, with the
+// build tags, into out, relative to the demo directory.
+func buildLinux(demo, arch, tags, out string) error {
+	env := map[string]string{"GOOS": "linux", "GOARCH": arch, "CGO_ENABLED": "0"}
+	return sh.RunWithV(env, goCompiler, "-C", demo, "build", "-tags", tags, "-o", out, ".")
+}
+
+// wasmTestExec is the script of the demos running their js/wasm tests in
+// Node.js, on a DOM of their own.
+const wasmTestExec = "test/go_js_wasm_exec"
+
+// Test runs the tests of a demo, or of all of them, along with their js/wasm
+// tests where they have some.
+func Test(demo string) error {
+	return forEach(demo, func(d string) error {
+		if err := sh.RunV(goCompiler, "-C", d, "test", "./..."); err != nil {
+			return err
+		}
+		exec, err := filepath.Abs(filepath.Join(d, wasmTestExec))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(exec); err != nil {
+			return nil
+		}
+		env := map[string]string{"GOOS": "js", "GOARCH": "wasm"}
+		return sh.RunWithV(env, goCompiler, "-C", d, "test", "-exec", exec, "./...")
+	})
+}
+
+// Run builds and runs a demo from its directory.
+func Run(demo string) error {
+	if demo == "all" {
+		return errors.New("run takes a single demo")
+	}
+	if err := Build(demo); err != nil {
+		return err
+	}
+	cmd := exec.Command(filepath.Join("bin", filepath.Base(appExecutable(demo))))
+	cmd.Dir = demo
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// Dev serves a demo on :8000, rebuilding it and reloading the open pages
+// whenever its sources or web directory change.
+func Dev(demo string) error {
+	if demo == "all" {
+		return errors.New("dev takes a single demo")
+	}
+	if !isDemo(demo) {
+		return fmt.Errorf("%s is not a demo directory", demo)
+	}
+	mod, err := os.ReadFile(filepath.Join(demo, "go.mod"))
+	if err != nil {
+		return err
+	}
+	var watch []string
+	for _, m := range localReplace.FindAllSubmatch(mod, -1) {
+		watch = append(watch, string(m[1]))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return devserver.Run(ctx, devserver.Config{Dir: demo, Watch: watch})
+}
+
+// Clean removes the build outputs of a demo, or of all of them.
+func Clean(demo string) error {
+	return forEach(demo, func(d string) error {
+		for _, f := range []string{wasmFile, wasmFile + ".br", wasmFile + ".gz"} {
+			if err := sh.Rm(filepath.Join(d, f)); err != nil {
+				return err
+			}
+		}
+		return sh.Rm(filepath.Join(d, "bin"))
+	})
+}
+
+// WasmSize reports the raw and gzipped size of web/app.wasm for a demo, or for
+// all of them, largest first.
+func WasmSize(demo string) error {
+	type size struct {
+		demo      string
+		raw, gzip int64
+	}
+	var sizes []size
+	err := forEach(demo, func(d string) error {
+		raw, gz, err := fileSizes(filepath.Join(d, wasmFile))
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%s not built", wasmFile)
+		}
+		if err != nil {
+			return err
+		}
+		sizes = append(sizes, size{demo: d, raw: raw, gzip: gz})
+		return nil
+	})
+
+	sort.Slice(sizes, func(i, j int) bool {
+		return sizes[i].raw > sizes[j].raw
+	})
+	for _, s := range sizes {
+		fmt.Printf("%-16s %8.2f MB %8.2f MB gzipped\n", s.demo, mb(s.raw), mb(s.gzip))
+	}
+	return err
+}
+
+func fileSizes(path string) (raw, gz int64, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := io.Copy(w, bytes.NewReader(b)); err != nil {
+		return 0, 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, 0, err
+	}
+	return int64(len(b)), int64(buf.Len()), nil
+}
+
+func mb(n int64) float64 {
+	return float64(n) / (1 << 20)
+}
@@ -0,0 +1,229 @@
+// Package clipboard copies to and reads from the system clipboard of the
+// browser running a go-app app, with whichever of its strategies the browser
+// supports, and without any JavaScript of its own:
+//
+//   - the asynchronous Clipboard API, navigator.clipboard, which browsers only
+//     expose in secure contexts: pages served over HTTPS or from localhost;
+//   - the execCommand("copy") fallback, copying a hidden textarea, for the
+//     pages served over plain HTTP.
+//
+// Browsers only let pages use the clipboard in response to a user action, so
+// the functions are meant to be called from the event handlers of the
+// components. They block until the browser is done, hence must run outside of
+// the UI goroutine, in ctx.Async:
+//
+//	ctx.Async(func() {
+//		err := clipboard.WriteText(ctx, text)
+//		ctx.Dispatch(func(ctx app.Context) {
+//			// Report the outcome.
+//		})
+//	})
+//
+// On the server, where there is no clipboard, they return ErrUnavailable.
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/interop"
+)
+
+var (
+	// ErrUnavailable is returned when the browser supports none of the
+	// strategies of the operation.
+	ErrUnavailable = errors.New("clipboard: not available")
+
+	// ErrNotAllowed matches the errors of the operations the browser or the
+	// user did not allow, such as a read the user denied the permission of.
+	ErrNotAllowed = errors.New("clipboard: not allowed")
+)
+
+// Error is the error of a clipboard operation the browser failed.
+type Error struct {
+	// The operation: "write" or "read".
+	Op string
+
+	// The name of the JavaScript error, such as NotAllowedError.
+	Name string
+
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("clipboard: %s: %s: %s", e.Op, e.Name, e.Message)
+}
+
+// Is reports whether the error is ErrNotAllowed.
+func (e *Error) Is(target error) bool {
+	return target == ErrNotAllowed && e.Name == "NotAllowedError"
+}
+
+// Strategy is a way of accessing the clipboard.
+type Strategy int
+
+const (
+	// None means that the clipboard is not accessible.
+	None Strategy = iota
+
+	// AsyncAPI is the asynchronous Clipboard API of the secure contexts.
+	AsyncAPI
+
+	// ExecCommand is the execCommand("copy") fallback, which only writes.
+	ExecCommand
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case AsyncAPI:
+		return "Clipboard API"
+	case ExecCommand:
+		return "execCommand"
+	default:
+		return "none"
+	}
+}
+
+// Detect returns the strategy the browser supports to write to the
+// clipboard, the asynchronous Clipboard API being preferred.
+func Detect() Strategy {
+	if app.IsServer {
+		return None
+	}
+	if SecureContext() && api().Get("writeText").Truthy() {
+		return AsyncAPI
+	}
+	doc := app.Window().Get("document")
+	if !doc.Get("execCommand").Truthy() {
+		return None
+	}
+	if doc.Get("queryCommandSupported").Truthy() && !doc.Call("queryCommandSupported", "copy").Bool() {
+		return None
+	}
+	return ExecCommand
+}
+
+// SecureContext reports whether the page is a secure context, in which the
+// browser exposes the asynchronous Clipboard API.
+func SecureContext() bool {
+	return !app.IsServer && app.Window().Get("isSecureContext").Bool()
+}
+
+// WriteText writes text to the clipboard. Should the browser reject the
+// write through the Clipboard API, which it does when the page is not
+// focused for instance, the execCommand fallback is tried before giving up.
+func WriteText(ctx context.Context, text string) error {
+	switch Detect() {
+	case AsyncAPI:
+		_, err := await(ctx, "write", api().Call("writeText", text))
+		if err == nil || errors.Is(err, ctx.Err()) {
+			return err
+		}
+		if execCopy(text) == nil {
+			return nil
+		}
+		return err
+	case ExecCommand:
+		return execCopy(text)
+	default:
+		return ErrUnavailable
+	}
+}
+
+// Write writes data, text by MIME type, such as text/plain and text/html, to
+// the clipboard as a single ClipboardItem: the application it is pasted into
+// picks the richest type it supports. Where ClipboardItem is not supported,
+// as in insecure contexts, only the text/plain data is written, by WriteText.
+func Write(ctx context.Context, data map[string]string) error {
+	text, hasText := data["text/plain"]
+	if !SecureContext() || !api().Get("write").Truthy() || !app.Window().Get("ClipboardItem").Truthy() {
+		if !hasText {
+			return ErrUnavailable
+		}
+		return WriteText(ctx, text)
+	}
+
+	blobs := make(map[string]any, len(data))
+	for typ, s := range data {
+		blobs[typ] = app.Window().Get("Blob").New([]any{s}, map[string]any{"type": typ})
+	}
+	items := app.Window().Get("Array").New()
+	items.Call("push", app.Window().Get("ClipboardItem").New(blobs))
+	_, err := await(ctx, "write", api().Call("write", items))
+	if err == nil || errors.Is(err, ctx.Err()) || !hasText {
+		return err
+	}
+	// The browser may not support one of the types.
+	if WriteText(ctx, text) == nil {
+		return nil
+	}
+	return err
+}
+
+// ReadText returns the text of the clipboard. It requires the asynchronous
+// Clipboard API, and the permission of the user, which the browser asks for.
+func ReadText(ctx context.Context) (string, error) {
+	if !SecureContext() || !api().Get("readText").Truthy() {
+		return "", ErrUnavailable
+	}
+	v, err := await(ctx, "read", api().Call("readText"))
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+// api returns navigator.clipboard.
+func api() app.Value {
+	return app.Window().Get("navigator").Get("clipboard")
+}
+
+// execCopy copies text by selecting it in a hidden textarea and executing the
+// copy command.
+func execCopy(text string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = jsError("write", r)
+		}
+	}()
+
+	doc := app.Window().Get("document")
+	ta := doc.Call("createElement", "textarea")
+	ta.Set("value", text)
+	ta.Call("setAttribute", "readonly", "")
+	style := ta.Get("style")
+	// Fixed, so that the page does not scroll to the textarea.
+	style.Set("position", "fixed")
+	style.Set("top", "0")
+	style.Set("opacity", "0")
+
+	body := doc.Get("body")
+	body.Call("appendChild", ta)
+	defer body.Call("removeChild", ta)
+	ta.Call("select")
+	if !doc.Call("execCommand", "copy").Bool() {
+		return &Error{Op: "write", Name: "ExecCommandError", Message: "the copy command failed"}
+	}
+	return nil
+}
+
+// await waits for the JavaScript promise p of the operation op to settle.
+func await(ctx context.Context, op string, p app.Value) (app.Value, error) {
+	v, err := interop.Await(ctx, p)
+	if err != nil && !errors.Is(err, ctx.Err()) {
+		return v, jsError(op, err)
+	}
+	return v, err
+}
+
+// jsError returns the error of the operation op from a JavaScript error, or
+// from the value a Go function recovered from.
+func jsError(op string, v any) error {
+	var e *interop.Error
+	if !errors.As(interop.ErrorOf(v), &e) {
+		e = &interop.Error{Name: "Error", Message: fmt.Sprint(v)}
+	}
+	return &Error{Op: op, Name: e.Name, Message: e.Message}
+}
@@ -0,0 +1,40 @@
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	if s := Detect(); s != None {
+		t.Errorf("Detect() = %v on the server, want %v", s, None)
+	}
+	if SecureContext() {
+		t.Error("secure context on the server")
+	}
+	if err := WriteText(context.Background(), "text"); err != ErrUnavailable {
+		t.Errorf("WriteText error = %v, want %v", err, ErrUnavailable)
+	}
+	if err := Write(context.Background(), map[string]string{"text/plain": "text", "text/html": "<b>text</b>"}); err != ErrUnavailable {
+		t.Errorf("Write error = %v, want %v", err, ErrUnavailable)
+	}
+	if _, err := ReadText(context.Background()); err != ErrUnavailable {
+		t.Errorf("ReadText error = %v, want %v", err, ErrUnavailable)
+	}
+}
+
+func TestError(t *testing.T) {
+	err := jsError("read", errors.New("JavaScript error: Read permission denied."))
+	if got, want := err.Error(), "clipboard: read: Error: Read permission denied."; got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+	if errors.Is(err, ErrNotAllowed) {
+		t.Errorf("%v is ErrNotAllowed", err)
+	}
+
+	err = &Error{Op: "read", Name: "NotAllowedError", Message: "Read permission denied."}
+	if !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("%v is not ErrNotAllowed", err)
+	}
+}
@@ -0,0 +1,58 @@
+// Command html2app prints the go-app code building the HTML of the files
+// given, or of the standard input:
+//
+//	go run ./cmd/html2app ../0B2-codecopy/test/index.html
+//
+// The warnings, such as duplicate attributes, are printed to the standard
+// error; with -strict, they make the command fail.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/suntong/go-app-demos/pkg/html2app"
+)
+
+func main() {
+	strict := flag.Bool("strict", false, "exit with status 1 when there are warnings")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: html2app [-strict] [file.html...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	status := 0
+	convert := func(name string, r io.Reader) {
+		code, warnings, err := html2app.Convert(r)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			status = 1
+			return
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, w)
+			if *strict {
+				status = 1
+			}
+		}
+		fmt.Print(code)
+	}
+
+	if flag.NArg() == 0 {
+		convert("stdin", os.Stdin)
+	}
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+		convert(name, f)
+		f.Close()
+	}
+	os.Exit(status)
+}
@@ -0,0 +1,106 @@
+// Package codeblock provides CodeBlock, a go-app component displaying source
+// code highlighted by chroma, a pure-Go syntax highlighter, with optional line
+// numbers, highlighted lines and a language badge.
+//
+// The highlighting depends on the fields of the component alone, so a code
+// block prerendered by the server is the very one the browser renders: search
+// engines index the highlighted code. Its classes are those of the chroma HTML
+// formatter, styled by the style sheet StyleSheet returns.
+package codeblock
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// CodeBlock is a component displaying highlighted source code.
+type CodeBlock struct {
+	app.Compo
+
+	// The ID of the code element.
+	ID string
+
+	// The source code, displayed dedented (see Dedent).
+	Code string
+
+	// The language of Code, a name or an alias known to chroma, such as "go"
+	// or "js". Guessed from Code when empty.
+	Language string
+
+	// Whether to number the lines.
+	LineNumbers bool
+
+	// The numbers of the lines to highlight, starting at 1.
+	Highlight []int
+
+	// Whether to hide the language badge.
+	NoBadge bool
+
+	// The chroma style of the HTML copies, which should be the one of the
+	// style sheet. Defaults to DefaultStyle.
+	Style string
+}
+
+// The Render method is where the component appearance is defined.
+func (c *CodeBlock) Render() app.UI {
+	name, lines := Lines(c.Code, c.Language)
+	return app.Div().Class("codeblock").DataSet("lang", name).Body(
+		app.If(!c.NoBadge && name != "",
+			app.Span().Class("codeblock-lang").Text(name),
+		),
+		app.Raw(c.html(lines)),
+	)
+}
+
+// html returns the pre element of the code block. It is raw HTML because the
+// server prerenders elements with line breaks between their children, which
+// a pre element would display.
+func (c *CodeBlock) html(lines [][]chroma.Token) string {
+	var b strings.Builder
+	b.WriteString(`<pre class="chroma"><code`)
+	if c.ID != "" {
+		fmt.Fprintf(&b, ` id="%s"`, html.EscapeString(c.ID))
+	}
+	b.WriteString(">")
+
+	digits := len(strconv.Itoa(len(lines)))
+	for i, tokens := range lines {
+		n := i + 1
+		if c.highlighted(n) {
+			fmt.Fprintf(&b, `<span class="%s %s">`, Class(chroma.Line), Class(chroma.LineHighlight))
+		} else {
+			fmt.Fprintf(&b, `<span class="%s">`, Class(chroma.Line))
+		}
+		if c.LineNumbers {
+			fmt.Fprintf(&b, `<span class="%s">%*d</span>`, Class(chroma.LineNumbers), digits, n)
+		}
+		fmt.Fprintf(&b, `<span class="%s">`, Class(chroma.CodeLine))
+		for _, t := range tokens {
+			if t.Value == "" {
+				continue
+			}
+			if cls := Class(t.Type); cls != "" {
+				fmt.Fprintf(&b, `<span class="%s">%s</span>`, cls, html.EscapeString(t.Value))
+			} else {
+				b.WriteString(html.EscapeString(t.Value))
+			}
+		}
+		b.WriteString("</span></span>")
+	}
+	b.WriteString("</code></pre>")
+	return b.String()
+}
+
+func (c *CodeBlock) highlighted(n int) bool {
+	for _, h := range c.Highlight {
+		if h == n {
+			return true
+		}
+	}
+	return false
+}
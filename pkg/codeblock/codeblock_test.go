@@ -0,0 +1,93 @@
+package codeblock
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+const helloWorld = `
+                    // Code block 1
+                    function helloWorld() {
+                        console.log("Hello, world!");
+                    }
+`
+
+func TestDedent(t *testing.T) {
+	want := "// Code block 1\nfunction helloWorld() {\n    console.log(\"Hello, world!\");\n}"
+	if got := Dedent(helloWorld); got != want {
+		t.Errorf("Dedent = %q, want %q", got, want)
+	}
+	if got := Dedent("\n\t\n"); got != "" {
+		t.Errorf("Dedent of blank lines = %q", got)
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		language, name string
+	}{
+		{"js", "JavaScript"},
+		{"JavaScript", "JavaScript"},
+		{"nolang", "nolang"},
+	}
+	for _, test := range tests {
+		name, lines := Lines(helloWorld, test.language)
+		if name != test.name {
+			t.Errorf("Lines(%q) name = %q, want %q", test.language, name, test.name)
+		}
+		if len(lines) != 4 {
+			t.Errorf("Lines(%q) = %d lines, want 4", test.language, len(lines))
+		}
+	}
+
+	if name, _ := Lines("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println()\n}\n", ""); name != "Go" {
+		t.Errorf("guessed %q, want Go", name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := &CodeBlock{
+		ID:          "code",
+		Code:        helloWorld,
+		Language:    "js",
+		LineNumbers: true,
+		Highlight:   []int{2},
+	}
+	html := app.HTMLString(c.Render())
+
+	// The lines follow each other with no whitespace in between, which the
+	// pre element would display.
+	for _, want := range []string{
+		`class="codeblock"`,
+		`data-lang="JavaScript"`,
+		`<span class="codeblock-lang">JavaScript</span>`,
+		`<pre class="chroma"><code id="code">`,
+		"<span class=\"line\"><span class=\"ln\">1</span><span class=\"cl\"><span class=\"c1\">// Code block 1\n</span></span></span><span class=\"line hl\">",
+		`<span class="line hl"><span class="ln">2</span><span class="cl"><span class="kd">function</span>`,
+		`<span class="line"><span class="ln">3</span><span class="cl">    <span class="nx">console</span>`,
+		`<span class="s2">&#34;Hello, world!&#34;</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("code block does not contain %s:\n%s", want, html)
+		}
+	}
+
+	c.LineNumbers, c.NoBadge = false, true
+	html = app.HTMLString(c.Render())
+	for _, unwanted := range []string{`class="ln"`, `class="codeblock-lang"`} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("code block contains %s:\n%s", unwanted, html)
+		}
+	}
+}
+
+func TestCSS(t *testing.T) {
+	css := CSS("github")
+	for _, want := range []string{".chroma .kd {", ".chroma .hl {", ".chroma .ln {", ".codeblock-lang {"} {
+		if !strings.Contains(css, want) {
+			t.Errorf("CSS does not contain %s", want)
+		}
+	}
+}
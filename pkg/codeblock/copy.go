@@ -0,0 +1,100 @@
+package codeblock
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/suntong/go-app-demos/pkg/clipboard"
+)
+
+// DefaultStyle is the chroma style of the HTML copies of the code blocks with
+// no Style.
+const DefaultStyle = "github"
+
+// Formats are the formats code is copied in, on top of plain text.
+type Formats int
+
+const (
+	// HTML adds the highlighted code as text/html, which rich text editors,
+	// such as those of documents and mails, paste.
+	HTML Formats = 1 << iota
+
+	// Markdown copies the plain text as a Markdown fenced code block, for
+	// chat tools and issue trackers.
+	Markdown
+)
+
+func (f Formats) String() string {
+	s := "text"
+	if f&Markdown != 0 {
+		s = "Markdown"
+	}
+	if f&HTML != 0 {
+		s += " and HTML"
+	}
+	return s
+}
+
+// Copy writes the code of the code block, dedented, to the clipboard in the
+// formats. Where the browser only copies plain text, as in insecure contexts,
+// the HTML is left out. It blocks until the browser is done: see package
+// clipboard.
+func (c *CodeBlock) Copy(ctx context.Context, formats Formats) error {
+	code := Dedent(c.Code)
+	text := code
+	if formats&Markdown != 0 {
+		text = MarkdownCode(code, c.Language)
+	}
+	if formats&HTML == 0 {
+		return clipboard.WriteText(ctx, text)
+	}
+
+	style := c.Style
+	if style == "" {
+		style = DefaultStyle
+	}
+	return clipboard.Write(ctx, map[string]string{
+		"text/plain": text,
+		"text/html":  HTMLCode(code, c.Language, style),
+	})
+}
+
+// HTMLCode returns code highlighted with the lexer of language, as for Lines,
+// in a pre element styled inline with the chroma style named style: the HTML
+// looks the same wherever it is pasted.
+func HTMLCode(code, language, style string) string {
+	_, lines := Lines(code, language)
+	var tokens []chroma.Token
+	for _, l := range lines {
+		for _, t := range l {
+			if t.Value != "" {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+
+	var b strings.Builder
+	f := html.New(html.WithClasses(false), html.TabWidth(4))
+	if err := f.Format(&b, styles.Get(style), chroma.Literator(tokens...)); err != nil {
+		panic(err)
+	}
+	return b.String()
+}
+
+// MarkdownCode returns code as a Markdown fenced code block, with the
+// language of the code as info string. The fence is longer than any run of
+// backticks of the code.
+func MarkdownCode(code, language string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if l := lexers.Get(language); l != nil && len(l.Config().Aliases) != 0 {
+		language = l.Config().Aliases[0]
+	}
+	return fence + language + "\n" + Dedent(code) + "\n" + fence + "\n"
+}
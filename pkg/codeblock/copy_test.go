@@ -0,0 +1,49 @@
+package codeblock
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suntong/go-app-demos/pkg/clipboard"
+)
+
+func TestMarkdownCode(t *testing.T) {
+	tests := []struct {
+		code, language, want string
+	}{
+		{helloWorld, "JavaScript", "```js\n// Code block 1\nfunction helloWorld() {\n    console.log(\"Hello, world!\");\n}\n```\n"},
+		{"a ``` b", "", "````\na ``` b\n````\n"},
+		{"x", "nolang", "```nolang\nx\n```\n"},
+	}
+	for _, test := range tests {
+		if got := MarkdownCode(test.code, test.language); got != test.want {
+			t.Errorf("MarkdownCode(%q, %q) = %q, want %q", test.code, test.language, got, test.want)
+		}
+	}
+}
+
+func TestHTMLCode(t *testing.T) {
+	html := HTMLCode(helloWorld, "js", DefaultStyle)
+	for _, want := range []string{
+		`<pre style="background-color:#fff;`,
+		`<span style="color:#000;font-weight:bold">function</span>`,
+		`&#34;Hello, world!&#34;`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML does not contain %s:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "class=") || strings.Contains(html, "<span></span>") {
+		t.Errorf("HTML is styled with classes, or has empty spans:\n%s", html)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	c := &CodeBlock{Code: helloWorld, Language: "js"}
+	for _, f := range []Formats{0, HTML, Markdown, HTML | Markdown} {
+		if err := c.Copy(context.Background(), f); err != clipboard.ErrUnavailable {
+			t.Errorf("copy as %v on the server: error = %v, want %v", f, err, clipboard.ErrUnavailable)
+		}
+	}
+}
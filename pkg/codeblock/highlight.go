@@ -0,0 +1,102 @@
+package codeblock
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Lines splits code, dedented, into lines of highlighted tokens, with the
+// lexer of language: a name or an alias known to chroma, such as "go" or
+// "js". The lexer is guessed from the code when language is empty, and plain
+// text is used when no lexer fits. It also returns the name of the lexer,
+// or language when unknown, for the badge of the code block.
+func Lines(code, language string) (string, [][]chroma.Token) {
+	code = Dedent(code)
+
+	lexer := lexers.Get(language)
+	if lexer == nil && language == "" {
+		lexer = lexers.Analyse(code)
+	}
+	name := language
+	if lexer != nil {
+		name = lexer.Config().Name
+	} else {
+		lexer = lexers.Fallback
+	}
+
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return name, chroma.SplitTokensIntoLines([]chroma.Token{{Type: chroma.Text, Value: code}})
+	}
+	return name, chroma.SplitTokensIntoLines(it.Tokens())
+}
+
+// Class returns the CSS class of the token type t, the one the chroma HTML
+// formatter uses, or "" when t is not styled.
+func Class(t chroma.TokenType) string {
+	for ; t != 0; t = t.Parent() {
+		if cls, ok := chroma.StandardTypes[t]; ok {
+			return cls
+		}
+	}
+	return chroma.StandardTypes[t]
+}
+
+// Dedent removes the blank lines around code and the indentation common to
+// its lines, as left by code written in an indented Go raw string.
+func Dedent(code string) string {
+	lines := strings.Split(strings.ReplaceAll(code, "\r\n", "\n"), "\n")
+	for len(lines) != 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent {
+			lines[i] = l[indent:]
+		} else {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CSS returns the style sheet of the code blocks with the chroma style named
+// style, such as "github" or "monokai", or the default style when unknown.
+func CSS(style string) string {
+	var b strings.Builder
+	f := html.New(html.WithClasses(true), html.WithLineNumbers(true))
+	if err := f.WriteCSS(&b, styles.Get(style)); err != nil {
+		panic(err)
+	}
+	b.WriteString(layoutCSS)
+	return b.String()
+}
+
+// StyleSheet returns CSS(style) within a style element, to be added to the
+// RawHeaders of the app.Handler.
+func StyleSheet(style string) string {
+	return "<style>\n" + CSS(style) + "</style>\n"
+}
+
+const layoutCSS = `/* CodeBlock */ .codeblock { position: relative }
+/* CodeBlock language badge */ .codeblock-lang { position: absolute; top: 0; left: 0; padding: 0 6px; font: 11px sans-serif; text-transform: uppercase; opacity: .6 }
+/* CodeBlock code */ .codeblock pre.chroma { margin: 0; padding: 18px 10px 10px; overflow-x: auto }
+/* CodeBlock line numbers */ .codeblock .ln { user-select: none }
+`
@@ -0,0 +1,234 @@
+package deploy
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// machines are the ELF machines of the architectures the demos are deployed
+// on.
+var machines = map[string]elf.Machine{
+	"amd64": elf.EM_X86_64,
+	"arm64": elf.EM_AARCH64,
+}
+
+var (
+	makefileOutput = regexp.MustCompile(`go build .*-o \./(\S+)`)
+	dockerCopy     = regexp.MustCompile(`(?m)^COPY (\S+) \S+$`)
+	dockerOutput   = regexp.MustCompile(`go build .*-o /out/(\S+)`)
+	dockerEntry    = regexp.MustCompile(`(?m)^ENTRYPOINT \["/([^"]+)"\]`)
+	dockerImage    = regexp.MustCompile(`(?m)^FROM golang:(\S+) `)
+)
+
+// Check cross-checks the artifacts written for the demo d, in its directory
+// relative to the repository root root, against its build: the binary the
+// makefile builds, the binary built for Space and systemd, the files the
+// Dockerfile copies and the executable of the Lambda zip. It reports every
+// mismatch found.
+func Check(root string, d Demo) error {
+	dir := filepath.Join(root, d.Dir)
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			report("%v", err)
+		}
+		return string(b)
+	}
+
+	// The makefile and the deploy config must agree on the binary, which
+	// must not be committed.
+	for _, m := range makefileOutput.FindAllStringSubmatch(read("makefile"), -1) {
+		if m[1] != d.Binary {
+			report("makefile builds ./%s, not the %s binary of %s", m[1], d.Binary, ConfigFile)
+		}
+	}
+	if !hasLine(read(".gitignore"), d.Binary, "/"+d.Binary) {
+		report(".gitignore does not list the %s binary", d.Binary)
+	}
+
+	// Space and systemd run the binary built for linux/amd64.
+	if err := checkExecutable(filepath.Join(dir, d.Binary), "amd64"); err != nil {
+		report("%v", err)
+	}
+	space := read("Spacefile")
+	for _, f := range yamlList(space, "include") {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			report("Spacefile includes %s, which the build did not produce", f)
+		}
+	}
+	if run := yamlValue(space, "run"); run != "./"+d.Binary {
+		report("Spacefile runs %q, not ./%s", run, d.Binary)
+	}
+
+	unit := read(filepath.Join(ArtifactDir, SystemdUnitFile(d)))
+	if exec := iniValue(unit, "ExecStart"); path.Base(exec) != d.Binary {
+		report("systemd unit runs %s, not the %s binary", exec, d.Binary)
+	}
+	if env := iniValue(unit, "Environment"); env != fmt.Sprintf("PORT=%d", d.Port) {
+		report("systemd unit sets %s, not PORT=%d", env, d.Port)
+	}
+
+	// The Dockerfile builds from the repository root.
+	docker := read("Dockerfile")
+	for _, m := range dockerCopy.FindAllStringSubmatch(docker, -1) {
+		if _, err := os.Stat(filepath.Join(root, m[1])); err != nil {
+			report("Dockerfile copies %s, which is not in the repository", m[1])
+		}
+	}
+	if m := dockerOutput.FindStringSubmatch(docker); m == nil || m[1] != d.Binary {
+		report("Dockerfile does not build the %s binary", d.Binary)
+	}
+	if m := dockerEntry.FindStringSubmatch(docker); m == nil || m[1] != d.Binary {
+		report("Dockerfile does not run the %s binary", d.Binary)
+	}
+	if m := dockerImage.FindStringSubmatch(docker); m == nil || versionLess(m[1], d.GoVersion) {
+		report("Dockerfile Go image is older than go %s", d.GoVersion)
+	}
+
+	if err := checkLambdaZip(filepath.Join(dir, ArtifactDir, LambdaZipFile), d.Lambda.Arch); err != nil {
+		report("%v", err)
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("%s deployment artifacts do not match the build:\n\t%s", d.Dir, strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// checkLambdaZip checks that the Lambda zip at name holds a single
+// executable, LambdaBootstrap, built for linux and arch.
+func checkLambdaZip(name, arch string) error {
+	z, err := zip.OpenReader(name)
+	if err != nil {
+		return err
+	}
+	defer z.Close()
+
+	if len(z.File) != 1 || z.File[0].Name != LambdaBootstrap {
+		return fmt.Errorf("%s must hold %s alone", name, LambdaBootstrap)
+	}
+	f := z.File[0]
+	if f.Mode()&0111 == 0 {
+		return fmt.Errorf("%s in %s is not executable", LambdaBootstrap, name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if err := checkELF(bytes.NewReader(b), arch); err != nil {
+		return fmt.Errorf("%s in %s: %w", LambdaBootstrap, name, err)
+	}
+	return nil
+}
+
+// checkExecutable checks that the file name is an executable built for linux
+// and arch.
+func checkExecutable(name, arch string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := checkELF(f, arch); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkELF(r io.ReaderAt, arch string) error {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return fmt.Errorf("not a linux executable: %w", err)
+	}
+	defer f.Close()
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return fmt.Errorf("not an executable but %v", f.Type)
+	}
+	if want := machines[arch]; f.Machine != want {
+		return fmt.Errorf("built for %v, not %s", f.Machine, arch)
+	}
+	return nil
+}
+
+// hasLine reports whether one of the lines of s is one of values.
+func hasLine(s string, values ...string) bool {
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		for _, v := range values {
+			if line == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// yamlValue returns the value of the first key: value line of the Spacefile
+// s.
+func yamlValue(s, key string) string {
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if v, ok := cutPrefix(line, key+":"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// yamlList returns the items of the first key: list of the Spacefile s.
+func yamlList(s, key string) []string {
+	var items []string
+	in := false
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == key+":":
+			in = true
+		case in && strings.HasPrefix(line, "- "):
+			items = append(items, strings.TrimSpace(strings.TrimPrefix(line, "- ")))
+		case in:
+			return items
+		}
+	}
+	return items
+}
+
+// iniValue returns the value of the first Key=value line of the systemd unit
+// s.
+func iniValue(s, key string) string {
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		if v, ok := cutPrefix(strings.TrimSpace(sc.Text()), key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
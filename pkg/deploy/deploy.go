@@ -0,0 +1,252 @@
+// Package deploy generates the deployment artifacts of a demo from the small
+// deploy.json config of its directory: a Spacefile for Deta Space, a
+// multi-stage Dockerfile, a systemd unit and an AWS Lambda deployment zip.
+// Check then cross-checks the artifacts against the build outputs, so that
+// names and paths never drift apart.
+package deploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ConfigFile is the name of the deploy config in a demo directory.
+const ConfigFile = "deploy.json"
+
+// Config is the deploy config of a demo.
+type Config struct {
+	// The name of the Space micro, the systemd unit and the Docker image.
+	// Defaults to the demo directory name.
+	Name string `json:"name"`
+
+	// The description of the systemd unit. Defaults to Name.
+	Description string `json:"description"`
+
+	// The server binary, as built by the makefile of the demo.
+	Binary string `json:"binary"`
+
+	// The port listened on, set through the PORT environment variable.
+	// Defaults to 8000.
+	Port int `json:"port"`
+
+	Lambda struct {
+		// The architecture of the Lambda function: arm64, the default, or
+		// amd64.
+		Arch string `json:"arch"`
+	} `json:"lambda"`
+
+	Systemd struct {
+		// The directory the binary is installed in. Defaults to
+		// /opt/<name>.
+		Dir string `json:"dir"`
+	} `json:"systemd"`
+}
+
+// Load reads the deploy config of the demo directory dir, and sets the
+// defaults of the fields left empty.
+func Load(dir string) (Config, error) {
+	var cfg Config
+	b, err := os.ReadFile(filepath.Join(dir, ConfigFile))
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+
+	if cfg.Binary == "" {
+		return cfg, fmt.Errorf("%s: binary is not set", ConfigFile)
+	}
+	if cfg.Name == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Name = filepath.Base(abs)
+	}
+	if cfg.Description == "" {
+		cfg.Description = cfg.Name
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 8000
+	}
+	switch cfg.Lambda.Arch {
+	case "":
+		cfg.Lambda.Arch = "arm64"
+	case "arm64", "amd64":
+	default:
+		return cfg, fmt.Errorf("%s: unsupported Lambda architecture %s", ConfigFile, cfg.Lambda.Arch)
+	}
+	if cfg.Systemd.Dir == "" {
+		cfg.Systemd.Dir = "/opt/" + cfg.Name
+	}
+	return cfg, nil
+}
+
+// Demo describes the demo the artifacts are generated for.
+type Demo struct {
+	Config
+
+	// The demo directory, relative to the repository root.
+	Dir string
+
+	// The local modules the demo replaces, relative to the repository root.
+	Replaces []string
+
+	// The Go version of the go.mod files.
+	GoVersion string
+}
+
+var (
+	localReplace = regexp.MustCompile(`=>\s*\.\./(\S+)`)
+	goDirective  = regexp.MustCompile(`(?m)^go\s+(\S+)`)
+)
+
+// LoadDemo loads the deploy config and the go.mod files of the demo directory
+// dir, relative to the repository root root.
+func LoadDemo(root, dir string) (Demo, error) {
+	cfg, err := Load(filepath.Join(root, dir))
+	if err != nil {
+		return Demo{}, err
+	}
+	d := Demo{Config: cfg, Dir: dir}
+
+	mod, err := os.ReadFile(filepath.Join(root, dir, "go.mod"))
+	if err != nil {
+		return Demo{}, err
+	}
+	for _, m := range localReplace.FindAllSubmatch(mod, -1) {
+		d.Replaces = append(d.Replaces, string(m[1]))
+	}
+
+	// The image must build every module: it takes the highest go directive.
+	for _, m := range append([]string{dir}, d.Replaces...) {
+		v, err := goVersion(filepath.Join(root, m, "go.mod"))
+		if err != nil {
+			return Demo{}, err
+		}
+		if d.GoVersion == "" || versionLess(d.GoVersion, v) {
+			d.GoVersion = v
+		}
+	}
+	return d, nil
+}
+
+// goVersion returns the go directive of the go.mod file at path.
+func goVersion(path string) (string, error) {
+	mod, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	m := goDirective.FindSubmatch(mod)
+	if m == nil {
+		return "", fmt.Errorf("%s has no go directive", path)
+	}
+	return string(m[1]), nil
+}
+
+// versionLess reports whether the Go version a, such as 1.19 or 1.21.3, is
+// lower than b.
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// Spacefile returns the Spacefile of the demo. Deta Space builds from the
+// demo directory alone, which lacks the local modules it replaces, so the
+// micro runs the linux/amd64 binary built beforehand, with web/ embedded.
+func Spacefile(d Demo) []byte {
+	return render(spacefileTmpl, d)
+}
+
+// Dockerfile returns the multi-stage Dockerfile of the demo, built from the
+// repository root so that the local modules the demo replaces are copied as
+// well.
+func Dockerfile(d Demo) []byte {
+	return render(dockerfileTmpl, d)
+}
+
+// SystemdUnit returns the systemd unit running the demo binary installed in
+// the Systemd.Dir directory.
+func SystemdUnit(d Demo) []byte {
+	return render(systemdTmpl, d)
+}
+
+// SystemdUnitFile returns the name of the systemd unit file.
+func SystemdUnitFile(d Demo) string {
+	return d.Name + ".service"
+}
+
+func render(t *template.Template, d Demo) []byte {
+	var b bytes.Buffer
+	if err := t.Execute(&b, d); err != nil {
+		panic(err)
+	}
+	return b.Bytes()
+}
+
+var spacefileTmpl = template.Must(template.New("Spacefile").Parse(`# Spacefile Docs: https://go.deta.dev/docs/spacefile/v0
+# Generated from {{.Dir}}/deploy.json by "go run mage.go deploy {{.Dir}}",
+# which builds {{.Binary}} for linux/amd64 with web/ embedded.
+v: 0
+micros:
+  - name: {{.Name}}
+    src: .
+    engine: custom
+    primary: true
+    include:
+      - {{.Binary}}
+    run: ./{{.Binary}}
+`))
+
+var dockerfileTmpl = template.Must(template.New("Dockerfile").Parse(`# Generated from {{.Dir}}/deploy.json by "go run mage.go deploy {{.Dir}}".
+# Build from the repository root:
+#   docker build -f {{.Dir}}/Dockerfile -t {{.Name}} .
+FROM golang:{{.GoVersion}} AS build
+WORKDIR /src
+{{range .Replaces}}COPY {{.}}/ {{.}}/
+{{end}}COPY {{.Dir}}/ {{.Dir}}/
+WORKDIR /src/{{.Dir}}
+RUN GOOS=js GOARCH=wasm go build -o web/app.wasm .
+RUN CGO_ENABLED=0 go build -tags embed -o /out/{{.Binary}} .
+
+FROM gcr.io/distroless/static-debian12
+COPY --from=build /out/{{.Binary}} /{{.Binary}}
+ENV PORT={{.Port}}
+EXPOSE {{.Port}}
+USER nonroot
+ENTRYPOINT ["/{{.Binary}}"]
+`))
+
+var systemdTmpl = template.Must(template.New("systemd").Parse(`# Generated from {{.Dir}}/deploy.json by "go run mage.go deploy {{.Dir}}".
+# Install {{.Binary}}, built with web/ embedded, as {{.Systemd.Dir}}/{{.Binary}},
+# and this file in /etc/systemd/system.
+[Unit]
+Description={{.Description}}
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart={{.Systemd.Dir}}/{{.Binary}}
+WorkingDirectory={{.Systemd.Dir}}
+Environment=PORT={{.Port}}
+DynamicUser=yes
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`))
@@ -0,0 +1,125 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "0S1-hello")
+	write(t, filepath.Join(dir, ConfigFile), `{"binary": "main"}`)
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "0S1-hello" || cfg.Description != "0S1-hello" || cfg.Port != 8000 ||
+		cfg.Lambda.Arch != "arm64" || cfg.Systemd.Dir != "/opt/0S1-hello" {
+		t.Errorf("defaults = %+v", cfg)
+	}
+
+	for _, config := range []string{`{}`, `{"binary": "main", "lambda": {"arch": "386"}}`, `{`} {
+		write(t, filepath.Join(dir, ConfigFile), config)
+		if _, err := Load(dir); err == nil {
+			t.Errorf("%s loaded", config)
+		}
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.19", "1.21", true},
+		{"1.21", "1.19", false},
+		{"1.21", "1.21.3", true},
+		{"1.9", "1.19", true},
+		{"1.21", "1.21", false},
+	}
+	for _, test := range tests {
+		if got := versionLess(test.a, test.b); got != test.want {
+			t.Errorf("versionLess(%s, %s) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+// TestWriteCheck writes the artifacts of a demo and checks them, with the
+// test executable standing for the binaries built for linux.
+func TestWriteCheck(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("needs a linux/amd64 test executable")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	write(t, filepath.Join(root, "pkg", "go.mod"), "module pkg\n\ngo 1.21\n")
+	write(t, filepath.Join(root, "demo", "go.mod"), "module demo\n\ngo 1.19\n\nreplace pkg => ../pkg\n")
+	write(t, filepath.Join(root, "demo", "makefile"), "build:\n\tgo build -o ./hello\n")
+	write(t, filepath.Join(root, "demo", ".gitignore"), "hello\n")
+	write(t, filepath.Join(root, "demo", ConfigFile), `{"binary": "hello", "port": 8080, "lambda": {"arch": "amd64"}}`)
+	write(t, filepath.Join(root, "demo", "hello"), string(b))
+
+	d, err := LoadDemo(root, "demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.GoVersion != "1.21" || len(d.Replaces) != 1 || d.Replaces[0] != "pkg" {
+		t.Errorf("demo = %+v, want go 1.21 replacing pkg", d)
+	}
+	if err := Write(root, d, filepath.Join(root, "demo", "hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(root, d); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []struct{ name, want string }{
+		{"Spacefile", "run: ./hello"},
+		{"Dockerfile", "COPY pkg/ pkg/"},
+		{"Dockerfile", `ENTRYPOINT ["/hello"]`},
+		{"deploy/demo.service", "ExecStart=/opt/demo/hello"},
+	} {
+		content, err := os.ReadFile(filepath.Join(root, "demo", f.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), f.want) {
+			t.Errorf("%s does not contain %q:\n%s", f.name, f.want, content)
+		}
+	}
+
+	// The makefile builds another binary, and the
+	// Lambda zip is built for the wrong architecture.
+	write(t, filepath.Join(root, "demo", "makefile"), "build:\n\tgo build -o ./main\n")
+	d.Lambda.Arch = "arm64"
+	err = Check(root, d)
+	if err == nil {
+		t.Fatal("mismatches not reported")
+	}
+	for _, want := range []string{"makefile builds ./main", "built for EM_X86_64, not arm64"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%v\ndoes not report %q", err, want)
+		}
+	}
+}
+
+func write(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
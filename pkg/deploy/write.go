@@ -0,0 +1,85 @@
+package deploy
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ArtifactDir is the directory of the demo the systemd unit and the
+	// Lambda zip are written in; the Spacefile and the Dockerfile are written
+	// in the demo directory.
+	ArtifactDir = "deploy"
+
+	// LambdaZipFile is the Lambda deployment zip, in ArtifactDir.
+	LambdaZipFile = "lambda.zip"
+
+	// LambdaBootstrap is the name of the executable of a Lambda function
+	// running on the provided.al2 runtime.
+	LambdaBootstrap = "bootstrap"
+)
+
+// Write writes the artifacts of the demo d into its directory, relative to
+// the repository root root. bootstrap is the Lambda function executable, the
+// demo server built for linux and Lambda.Arch.
+func Write(root string, d Demo, bootstrap string) error {
+	dir := filepath.Join(root, d.Dir)
+	if err := os.MkdirAll(filepath.Join(dir, ArtifactDir), 0755); err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"Spacefile":  Spacefile(d),
+		"Dockerfile": Dockerfile(d),
+		filepath.Join(ArtifactDir, SystemdUnitFile(d)): SystemdUnit(d),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, ArtifactDir, LambdaZipFile))
+	if err != nil {
+		return err
+	}
+	if err := WriteLambdaZip(f, bootstrap); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteLambdaZip writes the Lambda deployment zip, holding the executable
+// file bootstrap as LambdaBootstrap, to w.
+func WriteLambdaZip(w io.Writer, bootstrap string) error {
+	in, err := os.Open(bootstrap)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	h, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	h.Name = LambdaBootstrap
+	h.Method = zip.Deflate
+	h.SetMode(0755)
+
+	z := zip.NewWriter(w)
+	out, err := z.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return z.Close()
+}
@@ -0,0 +1,287 @@
+// Package devserver is a live-reload development server for the go-app demos.
+// It watches the Go sources and the web directory of a demo, rebuilds
+// web/app.wasm and the server binary when they change, restarts the server,
+// and tells the open pages to reload through a server-sent events endpoint.
+// Build errors are shown as an overlay in the pages, and the go-app service
+// worker is replaced by one that never serves from its cache.
+package devserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const wasmFile = "web/app.wasm"
+
+// logger writes to the standard error even when the standard logger is
+// silenced, as mage does without -v.
+var logger = log.New(os.Stderr, "", log.LstdFlags)
+
+// Config describes the demo to develop.
+type Config struct {
+	// The demo directory.
+	Dir string
+
+	// The address the development server listens on. Defaults to ":8000".
+	Addr string
+
+	// Additional directories whose Go sources trigger a rebuild, such as the
+	// local modules the demo replaces.
+	Watch []string
+
+	// How often the sources are checked for changes. Defaults to half a
+	// second.
+	Interval time.Duration
+}
+
+// Run serves the demo described by cfg, rebuilding and reloading it on
+// changes, until ctx is done.
+func Run(ctx context.Context, cfg Config) error {
+	if cfg.Addr == "" {
+		cfg.Addr = ":8000"
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = 500 * time.Millisecond
+	}
+
+	bin, err := os.MkdirTemp("", "devserver")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(bin)
+
+	backend, err := freeAddr()
+	if err != nil {
+		return err
+	}
+	d := &dev{
+		cfg:     cfg,
+		exe:     filepath.Join(bin, "server"),
+		backend: backend,
+		events:  newBroker(),
+	}
+	defer d.stop()
+
+	s := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: d.handler(),
+		// Ends the event streams of the open pages on shutdown.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.ListenAndServe()
+	}()
+	logger.Println("Developing", cfg.Dir, "on http://"+cfg.Addr)
+
+	d.rebuild()
+	w := newWatcher(cfg.Dir, cfg.Watch)
+	t := time.NewTicker(cfg.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return s.Shutdown(ctx)
+		case <-t.C:
+			switch c := w.scan(); {
+			case c.code:
+				d.rebuild()
+			case c.assets:
+				logger.Println("> Reloading", cfg.Dir)
+				d.events.reload()
+			}
+		}
+	}
+}
+
+// dev runs the demo server behind the development server.
+type dev struct {
+	cfg     Config
+	exe     string
+	backend string
+	events  *broker
+
+	mu     sync.Mutex
+	server *exec.Cmd
+	done   chan struct{}
+}
+
+func (d *dev) handler() http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = "http"
+			r.URL.Host = d.backend
+			// Pages are injected with the reload script, so they must not be
+			// compressed.
+			r.Header.Del("Accept-Encoding")
+			r.Header.Del("If-None-Match")
+			r.Header.Del("If-Modified-Since")
+		},
+		ModifyResponse: noCache,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			// The demo server is not up yet, or its build failed.
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write(inject([]byte(waitingPage)))
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(eventsPath, d.events)
+	mux.Handle(scriptPath, serveScript(reloadScript, "application/javascript"))
+	mux.Handle(workerPath, serveScript(devWorker, "application/javascript"))
+	mux.Handle("/", proxy)
+	return mux
+}
+
+// noCache injects the reload script into the pages, and prevents the browser
+// from caching anything.
+func noCache(res *http.Response) error {
+	res.Header.Set("Cache-Control", "no-store")
+	res.Header.Del("ETag")
+	res.Header.Del("Last-Modified")
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/html") {
+		return nil
+	}
+	page, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	page = inject(page)
+	res.Body = io.NopCloser(bytes.NewReader(page))
+	res.ContentLength = int64(len(page))
+	res.Header.Set("Content-Length", strconv.Itoa(len(page)))
+	return nil
+}
+
+// rebuild builds the demo and, when it succeeds, restarts its server and
+// reloads the open pages. Build errors are shown in the pages instead.
+func (d *dev) rebuild() {
+	logger.Println("> Building", d.cfg.Dir)
+	start := time.Now()
+
+	if out, err := d.build(); err != nil {
+		logger.Printf("> Build failed: %v\n%s", err, out)
+		d.events.fail(out)
+		return
+	}
+	if err := d.restart(); err != nil {
+		logger.Println("> Starting server failed:", err)
+		d.events.fail(err.Error())
+		return
+	}
+	logger.Println("> Ready in", time.Since(start).Round(time.Millisecond))
+	d.events.reload()
+}
+
+func (d *dev) build() (string, error) {
+	wasm := exec.Command("go", "build", "-o", wasmFile, ".")
+	wasm.Dir = d.cfg.Dir
+	wasm.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	if out, err := wasm.CombinedOutput(); err != nil {
+		return string(out), err
+	}
+
+	server := exec.Command("go", "build", "-o", d.exe, ".")
+	server.Dir = d.cfg.Dir
+	out, err := server.CombinedOutput()
+	return string(out), err
+}
+
+// restart stops the running demo server, if any, then starts the new one and
+// waits for it to be healthy.
+func (d *dev) restart() error {
+	d.stop()
+
+	cmd := exec.Command(d.exe, "-addr", d.backend)
+	cmd.Dir = d.cfg.Dir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	d.mu.Lock()
+	d.server, d.done = cmd, done
+	d.mu.Unlock()
+
+	return waitHealthy(d.backend, done)
+}
+
+// stop gracefully stops the running demo server.
+func (d *dev) stop() {
+	d.mu.Lock()
+	cmd, done := d.server, d.done
+	d.server, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cmd == nil {
+		return
+	}
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		<-done
+	}
+}
+
+// waitHealthy waits for the server listening on addr to answer its health
+// endpoint, or to exit.
+func waitHealthy(addr string, exited <-chan struct{}) error {
+	u := url.URL{Scheme: "http", Host: addr, Path: "/healthz"} // the pkg/server health endpoint
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case <-exited:
+			return errors.New("server exited")
+		default:
+		}
+		if res, err := http.Get(u.String()); err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("server did not answer on %s", addr)
+}
+
+// freeAddr returns a local address with a free port.
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
@@ -0,0 +1,118 @@
+package devserver
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInject(t *testing.T) {
+	tests := []struct {
+		page string
+		want string
+	}{
+		{
+			page: "<html><body><p>hi</p></body></html>",
+			want: `<html><body><p>hi</p><script src="/_dev/reload.js"></script></body></html>`,
+		},
+		{
+			page: "<p>hi</p>",
+			want: `<p>hi</p><script src="/_dev/reload.js"></script>`,
+		},
+	}
+	for _, test := range tests {
+		if got := string(inject([]byte(test.page))); got != test.want {
+			t.Errorf("inject(%q) = %q, want %q", test.page, got, test.want)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.go", "package main")
+	write("web/styles.css", "body {}")
+	write("web/app.wasm", "wasm")
+
+	w := newWatcher(dir, nil)
+	if c := w.scan(); c.code || c.assets {
+		t.Errorf("unchanged scan = %+v", c)
+	}
+
+	write("components/hello.go", "package components")
+	if c := w.scan(); !c.code || c.assets {
+		t.Errorf("Go source scan = %+v, want code", c)
+	}
+
+	write("web/styles.css", "body { margin: 0 }")
+	if c := w.scan(); c.code || !c.assets {
+		t.Errorf("asset scan = %+v, want assets", c)
+	}
+
+	write("web/app.wasm", "rebuilt wasm")
+	if c := w.scan(); c.code || c.assets {
+		t.Errorf("app.wasm scan = %+v, want no change", c)
+	}
+}
+
+func TestBroker(t *testing.T) {
+	b := newBroker()
+	b.fail("main.go:1: syntax error\nmain.go:2: syntax error")
+
+	s := httptest.NewServer(b)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	r := bufio.NewReader(res.Body)
+	want := "event: build-error\ndata: main.go:1: syntax error\ndata: main.go:2: syntax error\n\n"
+	if got := readEvent(t, r); got != want {
+		t.Errorf("replayed event = %q, want %q", got, want)
+	}
+
+	b.reload()
+	if got := readEvent(t, r); got != "event: reload\ndata: \n\n" {
+		t.Errorf("reload event = %q", got)
+	}
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		b.WriteString(line)
+		if line == "\n" || err == io.EOF {
+			return b.String()
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package devserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	eventsPath = "/_dev/events"
+	scriptPath = "/_dev/reload.js"
+	workerPath = "/app-worker.js"
+)
+
+// event is a server-sent event.
+type event struct {
+	name string
+	data string
+}
+
+// broker publishes the build events to the open pages.
+type broker struct {
+	mu      sync.Mutex
+	clients map[chan event]struct{}
+
+	// The last build error, replayed to the pages opened before it is fixed.
+	failure *event
+}
+
+func newBroker() *broker {
+	return &broker{clients: make(map[chan event]struct{})}
+}
+
+// reload tells the open pages to reload.
+func (b *broker) reload() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failure = nil
+	b.publish(event{name: "reload"})
+}
+
+// fail shows the build output as an overlay in the open pages.
+func (b *broker) fail(output string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failure = &event{name: "build-error", data: output}
+	b.publish(*b.failure)
+}
+
+func (b *broker) publish(e event) {
+	for c := range b.clients {
+		select {
+		case c <- e:
+		default:
+			// The page is not keeping up; it gets the next event.
+		}
+	}
+}
+
+func (b *broker) subscribe() chan event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := make(chan event, 8)
+	if b.failure != nil {
+		c <- *b.failure
+	}
+	b.clients[c] = struct{}{}
+	return c
+}
+
+func (b *broker) unsubscribe(c chan event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, c)
+}
+
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	c := b.subscribe()
+	defer b.unsubscribe(c)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-c:
+			writeEvent(w, e)
+			f.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e event) {
+	fmt.Fprintf(w, "event: %s\n", e.name)
+	for _, line := range strings.Split(e.data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// inject adds the reload script to an HTML page.
+func inject(page []byte) []byte {
+	tag := []byte(`<script src="` + scriptPath + `"></script>`)
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, tag...)
+	}
+	return append(page[:i:i], append(tag, page[i:]...)...)
+}
+
+// reloadScript reloads the page on the reload events, and shows the build
+// errors in an overlay.
+const reloadScript = `(function () {
+  var overlay = null;
+
+  function hide() {
+    if (overlay) {
+      overlay.remove();
+      overlay = null;
+    }
+  }
+
+  function show(output) {
+    hide();
+    overlay = document.createElement("pre");
+    overlay.id = "dev-build-error";
+    overlay.style.cssText = "position:fixed;inset:0;margin:0;padding:2rem;z-index:99999;" +
+      "overflow:auto;background:rgba(20,0,0,.92);color:#ffb4b4;font:14px/1.4 monospace;white-space:pre-wrap";
+    overlay.textContent = "Build failed\n\n" + output;
+    document.body.appendChild(overlay);
+  }
+
+  var events = new EventSource("` + eventsPath + `");
+  events.addEventListener("reload", function () {
+    location.reload();
+  });
+  events.addEventListener("build-error", function (e) {
+    show(e.data);
+  });
+})();
+`
+
+// devWorker replaces the go-app service worker during development: it clears
+// the caches the go-app worker filled, and always fetches from the network.
+const devWorker = `self.addEventListener("install", function () {
+  self.skipWaiting();
+});
+
+self.addEventListener("activate", function (event) {
+  event.waitUntil(
+    caches.keys()
+      .then(function (keys) {
+        return Promise.all(keys.map(function (key) { return caches.delete(key); }));
+      })
+      .then(function () { return self.clients.claim(); })
+  );
+});
+
+self.addEventListener("fetch", function (event) {
+  event.respondWith(fetch(event.request, { cache: "no-store" }));
+});
+`
+
+// waitingPage is served until the demo server runs for the first time.
+const waitingPage = `<!DOCTYPE html>
+<html>
+<head><title>Building...</title></head>
+<body><p>Waiting for the demo to build...</p></body>
+</html>
+`
+
+func serveScript(content, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte(content))
+	}
+}
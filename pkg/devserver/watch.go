@@ -0,0 +1,105 @@
+package devserver
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// changes tells what kind of files changed between two scans.
+type changes struct {
+	// Go sources, go.mod or go.sum changed: the demo must be rebuilt.
+	code bool
+
+	// Files of the web directory changed: open pages must be reloaded.
+	assets bool
+}
+
+// watcher polls the demo directory, and the additional directories holding Go
+// sources, for changes.
+type watcher struct {
+	dir   string
+	extra []string
+	files map[string]fileState
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func newWatcher(dir string, extra []string) *watcher {
+	w := &watcher{dir: dir, extra: extra}
+	w.files = w.snapshot()
+	return w
+}
+
+// scan reports what changed since the previous scan.
+func (w *watcher) scan() changes {
+	files := w.snapshot()
+
+	var c changes
+	mark := func(path string) {
+		if isAsset(w.dir, path) {
+			c.assets = true
+		} else {
+			c.code = true
+		}
+	}
+	for path, s := range files {
+		if prev, ok := w.files[path]; !ok || prev != s {
+			mark(path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := files[path]; !ok {
+			mark(path)
+		}
+	}
+
+	w.files = files
+	return c
+}
+
+func (w *watcher) snapshot() map[string]fileState {
+	files := make(map[string]fileState)
+	add := func(path string, d fs.DirEntry) {
+		if info, err := d.Info(); err == nil {
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+
+	for _, root := range append([]string{w.dir}, w.extra...) {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && skipDir(d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch {
+			case path == filepath.Join(w.dir, wasmFile):
+			case isAsset(w.dir, path):
+				add(path, d)
+			case strings.HasSuffix(path, ".go"), d.Name() == "go.mod", d.Name() == "go.sum":
+				add(path, d)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// isAsset reports whether path is in the web directory of the demo.
+func isAsset(dir, path string) bool {
+	return strings.HasPrefix(path, filepath.Join(dir, "web")+string(os.PathSeparator))
+}
+
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "bin" || name == "dist" || name == "node_modules"
+}
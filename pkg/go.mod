@@ -0,0 +1,16 @@
+module github.com/suntong/go-app-demos/pkg
+
+go 1.19
+
+require (
+	github.com/akrylysov/algnhsa v1.0.0
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/aws/aws-lambda-go v1.37.0
+	github.com/maxence-charriere/go-app/v9 v9.8.0
+	golang.org/x/net v0.12.0
+)
+
+require (
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+)
@@ -0,0 +1,381 @@
+// Code generated by gen.go from go-app v9.8.0; DO NOT EDIT.
+
+package html2app
+
+var globalAttrs = map[string]attr{
+	"accesskey":       {"AccessKey", "string"},
+	"contenteditable": {"ContentEditable", "bool"},
+	"dir":             {"Dir", "string"},
+	"draggable":       {"Draggable", "bool"},
+	"hidden":          {"Hidden", "bool"},
+	"id":              {"ID", "string"},
+	"lang":            {"Lang", "string"},
+	"role":            {"Role", "string"},
+	"tabindex":        {"TabIndex", "int"},
+	"title":           {"Title", "string"},
+}
+
+var elements = map[string]element{
+	"a": {Func: "A", Void: false, Attrs: map[string]attr{
+		"download": {"Download", "string"},
+		"href":     {"Href", "string"},
+		"hreflang": {"HrefLang", "string"},
+		"media":    {"Media", "string"},
+		"ping":     {"Ping", "string"},
+		"rel":      {"Rel", "string"},
+		"target":   {"Target", "string"},
+		"type":     {"Type", "string"},
+	}},
+	"abbr":    {Func: "Abbr", Void: false, Attrs: map[string]attr{}},
+	"address": {Func: "Address", Void: false, Attrs: map[string]attr{}},
+	"area": {Func: "Area", Void: true, Attrs: map[string]attr{
+		"alt":      {"Alt", "string"},
+		"coords":   {"Coords", "string"},
+		"download": {"Download", "string"},
+		"href":     {"Href", "string"},
+		"hreflang": {"HrefLang", "string"},
+		"media":    {"Media", "string"},
+		"rel":      {"Rel", "string"},
+		"shape":    {"Shape", "string"},
+		"target":   {"Target", "string"},
+		"type":     {"Type", "string"},
+	}},
+	"article": {Func: "Article", Void: false, Attrs: map[string]attr{}},
+	"aside":   {Func: "Aside", Void: false, Attrs: map[string]attr{}},
+	"audio": {Func: "Audio", Void: false, Attrs: map[string]attr{
+		"autoplay":    {"AutoPlay", "bool"},
+		"controls":    {"Controls", "bool"},
+		"crossorigin": {"CrossOrigin", "string"},
+		"loop":        {"Loop", "bool"},
+		"muted":       {"Muted", "bool"},
+		"preload":     {"Preload", "string"},
+		"src":         {"Src", "string"},
+	}},
+	"b": {Func: "B", Void: false, Attrs: map[string]attr{}},
+	"base": {Func: "Base", Void: true, Attrs: map[string]attr{
+		"href":   {"Href", "string"},
+		"target": {"Target", "string"},
+	}},
+	"bdi": {Func: "Bdi", Void: false, Attrs: map[string]attr{}},
+	"bdo": {Func: "Bdo", Void: false, Attrs: map[string]attr{}},
+	"blockquote": {Func: "Blockquote", Void: false, Attrs: map[string]attr{
+		"cite": {"Cite", "string"},
+	}},
+	"body": {Func: "Body", Void: false, Attrs: map[string]attr{}},
+	"br":   {Func: "Br", Void: true, Attrs: map[string]attr{}},
+	"button": {Func: "Button", Void: false, Attrs: map[string]attr{
+		"autofocus":      {"AutoFocus", "bool"},
+		"disabled":       {"Disabled", "bool"},
+		"form":           {"Form", "string"},
+		"formaction":     {"FormAction", "string"},
+		"formenctype":    {"FormEncType", "string"},
+		"formmethod":     {"FormMethod", "string"},
+		"formnovalidate": {"FormNoValidate", "bool"},
+		"formtarget":     {"FormTarget", "string"},
+		"name":           {"Name", "string"},
+		"type":           {"Type", "string"},
+		"value":          {"Value", "any"},
+	}},
+	"canvas": {Func: "Canvas", Void: false, Attrs: map[string]attr{
+		"height": {"Height", "int"},
+		"width":  {"Width", "int"},
+	}},
+	"caption": {Func: "Caption", Void: false, Attrs: map[string]attr{}},
+	"cite":    {Func: "Cite", Void: false, Attrs: map[string]attr{}},
+	"code":    {Func: "Code", Void: false, Attrs: map[string]attr{}},
+	"col": {Func: "Col", Void: true, Attrs: map[string]attr{
+		"span": {"Span", "int"},
+	}},
+	"colgroup": {Func: "ColGroup", Void: false, Attrs: map[string]attr{
+		"span": {"Span", "int"},
+	}},
+	"data": {Func: "Data", Void: false, Attrs: map[string]attr{
+		"value": {"Value", "any"},
+	}},
+	"datalist": {Func: "DataList", Void: false, Attrs: map[string]attr{}},
+	"dd":       {Func: "Dd", Void: false, Attrs: map[string]attr{}},
+	"del": {Func: "Del", Void: false, Attrs: map[string]attr{
+		"cite":     {"Cite", "string"},
+		"datetime": {"DateTime", "string"},
+	}},
+	"details": {Func: "Details", Void: false, Attrs: map[string]attr{
+		"open": {"Open", "bool"},
+	}},
+	"dfn": {Func: "Dfn", Void: false, Attrs: map[string]attr{}},
+	"dialog": {Func: "Dialog", Void: false, Attrs: map[string]attr{
+		"open": {"Open", "bool"},
+	}},
+	"div": {Func: "Div", Void: false, Attrs: map[string]attr{}},
+	"dl":  {Func: "Dl", Void: false, Attrs: map[string]attr{}},
+	"dt":  {Func: "Dt", Void: false, Attrs: map[string]attr{}},
+	"em":  {Func: "Em", Void: false, Attrs: map[string]attr{}},
+	"embed": {Func: "Embed", Void: true, Attrs: map[string]attr{
+		"height": {"Height", "int"},
+		"src":    {"Src", "string"},
+		"type":   {"Type", "string"},
+		"width":  {"Width", "int"},
+	}},
+	"fieldset": {Func: "FieldSet", Void: false, Attrs: map[string]attr{
+		"disabled": {"Disabled", "bool"},
+		"form":     {"Form", "string"},
+		"name":     {"Name", "string"},
+	}},
+	"figcaption": {Func: "FigCaption", Void: false, Attrs: map[string]attr{}},
+	"figure":     {Func: "Figure", Void: false, Attrs: map[string]attr{}},
+	"footer":     {Func: "Footer", Void: false, Attrs: map[string]attr{}},
+	"form": {Func: "Form", Void: false, Attrs: map[string]attr{
+		"accept-charset": {"AcceptCharset", "string"},
+		"action":         {"Action", "string"},
+		"enctype":        {"EncType", "string"},
+		"method":         {"Method", "string"},
+		"name":           {"Name", "string"},
+		"novalidate":     {"NoValidate", "bool"},
+		"target":         {"Target", "string"},
+	}},
+	"h1":     {Func: "H1", Void: false, Attrs: map[string]attr{}},
+	"h2":     {Func: "H2", Void: false, Attrs: map[string]attr{}},
+	"h3":     {Func: "H3", Void: false, Attrs: map[string]attr{}},
+	"h4":     {Func: "H4", Void: false, Attrs: map[string]attr{}},
+	"h5":     {Func: "H5", Void: false, Attrs: map[string]attr{}},
+	"h6":     {Func: "H6", Void: false, Attrs: map[string]attr{}},
+	"head":   {Func: "Head", Void: false, Attrs: map[string]attr{}},
+	"header": {Func: "Header", Void: false, Attrs: map[string]attr{}},
+	"hr":     {Func: "Hr", Void: true, Attrs: map[string]attr{}},
+	"html":   {Func: "Html", Void: false, Attrs: map[string]attr{}},
+	"i":      {Func: "I", Void: false, Attrs: map[string]attr{}},
+	"iframe": {Func: "IFrame", Void: false, Attrs: map[string]attr{
+		"allow":          {"Allow", "string"},
+		"height":         {"Height", "int"},
+		"loading":        {"Loading", "string"},
+		"name":           {"Name", "string"},
+		"referrerpolicy": {"ReferrerPolicy", "string"},
+		"sandbox":        {"Sandbox", "any"},
+		"src":            {"Src", "string"},
+		"srcdoc":         {"SrcDoc", "string"},
+		"width":          {"Width", "int"},
+	}},
+	"img": {Func: "Img", Void: true, Attrs: map[string]attr{
+		"alt":           {"Alt", "string"},
+		"crossorigin":   {"CrossOrigin", "string"},
+		"fetchpriority": {"FetchPriority", "string"},
+		"height":        {"Height", "int"},
+		"ismap":         {"IsMap", "bool"},
+		"sizes":         {"Sizes", "string"},
+		"src":           {"Src", "string"},
+		"srcset":        {"SrcSet", "string"},
+		"usemap":        {"UseMap", "string"},
+		"width":         {"Width", "int"},
+	}},
+	"input": {Func: "Input", Void: true, Attrs: map[string]attr{
+		"accept":         {"Accept", "string"},
+		"alt":            {"Alt", "string"},
+		"autofocus":      {"AutoFocus", "bool"},
+		"capture":        {"Capture", "string"},
+		"checked":        {"Checked", "bool"},
+		"dirname":        {"DirName", "string"},
+		"disabled":       {"Disabled", "bool"},
+		"form":           {"Form", "string"},
+		"formaction":     {"FormAction", "string"},
+		"formenctype":    {"FormEncType", "string"},
+		"formmethod":     {"FormMethod", "string"},
+		"formnovalidate": {"FormNoValidate", "bool"},
+		"formtarget":     {"FormTarget", "string"},
+		"height":         {"Height", "int"},
+		"list":           {"List", "string"},
+		"max":            {"Max", "any"},
+		"maxlength":      {"MaxLength", "int"},
+		"min":            {"Min", "any"},
+		"multiple":       {"Multiple", "bool"},
+		"name":           {"Name", "string"},
+		"pattern":        {"Pattern", "string"},
+		"placeholder":    {"Placeholder", "string"},
+		"readonly":       {"ReadOnly", "bool"},
+		"required":       {"Required", "bool"},
+		"size":           {"Size", "int"},
+		"src":            {"Src", "string"},
+		"step":           {"Step", "float64"},
+		"type":           {"Type", "string"},
+		"value":          {"Value", "any"},
+		"width":          {"Width", "int"},
+	}},
+	"ins": {Func: "Ins", Void: false, Attrs: map[string]attr{}},
+	"kbd": {Func: "Kbd", Void: false, Attrs: map[string]attr{}},
+	"label": {Func: "Label", Void: false, Attrs: map[string]attr{
+		"for":  {"For", "string"},
+		"form": {"Form", "string"},
+	}},
+	"legend": {Func: "Legend", Void: false, Attrs: map[string]attr{}},
+	"li": {Func: "Li", Void: false, Attrs: map[string]attr{
+		"value": {"Value", "any"},
+	}},
+	"link": {Func: "Link", Void: true, Attrs: map[string]attr{
+		"as":            {"As", "string"},
+		"crossorigin":   {"CrossOrigin", "string"},
+		"fetchpriority": {"FetchPriority", "string"},
+		"href":          {"Href", "string"},
+		"hreflang":      {"HrefLang", "string"},
+		"media":         {"Media", "string"},
+		"rel":           {"Rel", "string"},
+		"sizes":         {"Sizes", "string"},
+		"type":          {"Type", "string"},
+	}},
+	"main": {Func: "Main", Void: false, Attrs: map[string]attr{}},
+	"map": {Func: "Map", Void: false, Attrs: map[string]attr{
+		"name": {"Name", "string"},
+	}},
+	"mark": {Func: "Mark", Void: false, Attrs: map[string]attr{}},
+	"meta": {Func: "Meta", Void: true, Attrs: map[string]attr{
+		"charset":    {"Charset", "string"},
+		"content":    {"Content", "string"},
+		"http-equiv": {"HTTPEquiv", "string"},
+		"name":       {"Name", "string"},
+		"property":   {"Property", "string"},
+	}},
+	"meter": {Func: "Meter", Void: false, Attrs: map[string]attr{
+		"form":    {"Form", "string"},
+		"high":    {"High", "float64"},
+		"low":     {"Low", "float64"},
+		"max":     {"Max", "any"},
+		"min":     {"Min", "any"},
+		"optimum": {"Optimum", "float64"},
+		"value":   {"Value", "any"},
+	}},
+	"nav":      {Func: "Nav", Void: false, Attrs: map[string]attr{}},
+	"noscript": {Func: "NoScript", Void: false, Attrs: map[string]attr{}},
+	"object": {Func: "Object", Void: false, Attrs: map[string]attr{
+		"data":   {"Data", "string"},
+		"form":   {"Form", "string"},
+		"height": {"Height", "int"},
+		"name":   {"Name", "string"},
+		"type":   {"Type", "string"},
+		"usemap": {"UseMap", "string"},
+		"width":  {"Width", "int"},
+	}},
+	"ol": {Func: "Ol", Void: false, Attrs: map[string]attr{
+		"reversed": {"Reversed", "bool"},
+		"start":    {"Start", "int"},
+		"type":     {"Type", "string"},
+	}},
+	"optgroup": {Func: "OptGroup", Void: false, Attrs: map[string]attr{
+		"disabled": {"Disabled", "bool"},
+		"label":    {"Label", "string"},
+	}},
+	"option": {Func: "Option", Void: false, Attrs: map[string]attr{
+		"disabled": {"Disabled", "bool"},
+		"label":    {"Label", "string"},
+		"selected": {"Selected", "bool"},
+		"value":    {"Value", "any"},
+	}},
+	"output": {Func: "Output", Void: false, Attrs: map[string]attr{
+		"for":  {"For", "string"},
+		"form": {"Form", "string"},
+		"name": {"Name", "string"},
+	}},
+	"p": {Func: "P", Void: false, Attrs: map[string]attr{}},
+	"param": {Func: "Param", Void: true, Attrs: map[string]attr{
+		"name":  {"Name", "string"},
+		"value": {"Value", "any"},
+	}},
+	"picture": {Func: "Picture", Void: false, Attrs: map[string]attr{}},
+	"pre":     {Func: "Pre", Void: false, Attrs: map[string]attr{}},
+	"progress": {Func: "Progress", Void: false, Attrs: map[string]attr{
+		"max":   {"Max", "any"},
+		"value": {"Value", "any"},
+	}},
+	"q": {Func: "Q", Void: false, Attrs: map[string]attr{
+		"cite": {"Cite", "string"},
+	}},
+	"rp":   {Func: "Rp", Void: false, Attrs: map[string]attr{}},
+	"rt":   {Func: "Rt", Void: false, Attrs: map[string]attr{}},
+	"ruby": {Func: "Ruby", Void: false, Attrs: map[string]attr{}},
+	"s":    {Func: "S", Void: false, Attrs: map[string]attr{}},
+	"samp": {Func: "Samp", Void: false, Attrs: map[string]attr{}},
+	"script": {Func: "Script", Void: false, Attrs: map[string]attr{
+		"async":       {"Async", "bool"},
+		"charset":     {"Charset", "string"},
+		"crossorigin": {"CrossOrigin", "string"},
+		"defer":       {"Defer", "bool"},
+		"src":         {"Src", "string"},
+		"type":        {"Type", "string"},
+	}},
+	"section": {Func: "Section", Void: false, Attrs: map[string]attr{}},
+	"select": {Func: "Select", Void: false, Attrs: map[string]attr{
+		"autofocus": {"AutoFocus", "bool"},
+		"disabled":  {"Disabled", "bool"},
+		"form":      {"Form", "string"},
+		"multiple":  {"Multiple", "bool"},
+		"name":      {"Name", "string"},
+		"required":  {"Required", "bool"},
+		"size":      {"Size", "int"},
+	}},
+	"small": {Func: "Small", Void: false, Attrs: map[string]attr{}},
+	"source": {Func: "Source", Void: true, Attrs: map[string]attr{
+		"media":  {"Media", "string"},
+		"sizes":  {"Sizes", "string"},
+		"src":    {"Src", "string"},
+		"srcset": {"SrcSet", "string"},
+		"type":   {"Type", "string"},
+	}},
+	"span":   {Func: "Span", Void: false, Attrs: map[string]attr{}},
+	"strong": {Func: "Strong", Void: false, Attrs: map[string]attr{}},
+	"style": {Func: "Style", Void: false, Attrs: map[string]attr{
+		"media": {"Media", "string"},
+		"type":  {"Type", "string"},
+	}},
+	"sub":     {Func: "Sub", Void: false, Attrs: map[string]attr{}},
+	"summary": {Func: "Summary", Void: false, Attrs: map[string]attr{}},
+	"sup":     {Func: "Sup", Void: false, Attrs: map[string]attr{}},
+	"table":   {Func: "Table", Void: false, Attrs: map[string]attr{}},
+	"tbody":   {Func: "TBody", Void: false, Attrs: map[string]attr{}},
+	"td": {Func: "Td", Void: false, Attrs: map[string]attr{
+		"colspan": {"ColSpan", "int"},
+		"headers": {"Headers", "string"},
+		"rowspan": {"Rowspan", "int"},
+	}},
+	"template": {Func: "Template", Void: false, Attrs: map[string]attr{}},
+	"textarea": {Func: "Textarea", Void: false, Attrs: map[string]attr{
+		"autofocus":   {"AutoFocus", "bool"},
+		"cols":        {"Cols", "int"},
+		"dirname":     {"DirName", "string"},
+		"disabled":    {"Disabled", "bool"},
+		"form":        {"Form", "string"},
+		"maxlength":   {"MaxLength", "int"},
+		"name":        {"Name", "string"},
+		"placeholder": {"Placeholder", "string"},
+		"readonly":    {"ReadOnly", "bool"},
+		"required":    {"Required", "bool"},
+		"rows":        {"Rows", "int"},
+		"value":       {"Text", "any"},
+		"wrap":        {"Wrap", "string"},
+	}},
+	"tfoot": {Func: "TFoot", Void: false, Attrs: map[string]attr{}},
+	"th": {Func: "Th", Void: false, Attrs: map[string]attr{
+		"abbr":    {"Abbr", "string"},
+		"colspan": {"ColSpan", "int"},
+		"headers": {"Headers", "string"},
+		"rowspan": {"Rowspan", "int"},
+		"scope":   {"Scope", "string"},
+	}},
+	"thead": {Func: "THead", Void: false, Attrs: map[string]attr{}},
+	"time": {Func: "Time", Void: false, Attrs: map[string]attr{
+		"datetime": {"DateTime", "string"},
+	}},
+	"title": {Func: "Title", Void: false, Attrs: map[string]attr{}},
+	"tr":    {Func: "Tr", Void: false, Attrs: map[string]attr{}},
+	"u":     {Func: "U", Void: false, Attrs: map[string]attr{}},
+	"ul":    {Func: "Ul", Void: false, Attrs: map[string]attr{}},
+	"var":   {Func: "Var", Void: false, Attrs: map[string]attr{}},
+	"video": {Func: "Video", Void: false, Attrs: map[string]attr{
+		"autoplay":    {"AutoPlay", "bool"},
+		"controls":    {"Controls", "bool"},
+		"crossorigin": {"CrossOrigin", "string"},
+		"height":      {"Height", "int"},
+		"loop":        {"Loop", "bool"},
+		"muted":       {"Muted", "bool"},
+		"poster":      {"Poster", "string"},
+		"preload":     {"Preload", "string"},
+		"src":         {"Src", "string"},
+		"width":       {"Width", "int"},
+	}},
+	"wbr": {Func: "Wbr", Void: false, Attrs: map[string]attr{}},
+}
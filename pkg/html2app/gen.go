@@ -0,0 +1,241 @@
+//go:build ignore
+
+// gen.go generates elements.go from the HTML elements of go-app, those of its
+// html_gen.go: their builder functions and the attributes their methods set.
+// Run it with go generate, after upgrading go-app.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const goApp = "github.com/maxence-charriere/go-app/v9"
+
+// element is an HTML element of go-app, such as HTMLDiv.
+type element struct {
+	tag   string
+	fn    string
+	void  bool
+	attrs map[string]attr
+}
+
+// attr is a method of an element setting an attribute, such as Href.
+type attr struct {
+	Method string
+	Type   string
+}
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}} {{.Version}}", goApp).Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir, version, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join(dir, "pkg", "app", "html_gen.go"), nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The elements, by the type of their implementation, such as htmlDiv.
+	elements := make(map[string]*element)
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Type.Params.NumFields() != 0 {
+			continue
+		}
+		if e := newElement(fd); e != nil {
+			elements["html"+fd.Name.Name] = e
+		}
+	}
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil {
+			continue
+		}
+		star, ok := fd.Recv.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		e := elements[star.X.(*ast.Ident).Name]
+		if e == nil {
+			continue
+		}
+		// Textarea has Text and Textf for its value: the first one
+		// listed is kept.
+		if name, a, ok := attrMethod(fd); ok && e.attrs[name] == (attr{}) {
+			e.attrs[name] = a
+		}
+	}
+
+	// The attributes every element has a method for, such as class and id,
+	// are listed once.
+	var all []*element
+	for _, e := range elements {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].tag < all[j].tag })
+	global := make(map[string]attr)
+	for name, a := range all[0].attrs {
+		global[name] = a
+	}
+	for _, e := range all[1:] {
+		for name, a := range global {
+			if e.attrs[name] != a {
+				delete(global, name)
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen.go from go-app %s; DO NOT EDIT.\n\n", version)
+	fmt.Fprintln(&b, "package html2app")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "var globalAttrs = map[string]attr{")
+	writeAttrs(&b, global, nil)
+	fmt.Fprintln(&b, "}")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "var elements = map[string]element{")
+	for _, e := range all {
+		fmt.Fprintf(&b, "%q: {Func: %q, Void: %t, Attrs: map[string]attr{\n", e.tag, e.fn, e.void)
+		writeAttrs(&b, e.attrs, global)
+		fmt.Fprintln(&b, "}},")
+	}
+	fmt.Fprintln(&b, "}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("elements.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// newElement returns the element fd builds, such as:
+//
+//	func Div() HTMLDiv {
+//		e := &htmlDiv{
+//			htmlElement: htmlElement{
+//				tag:           "div",
+//				isSelfClosing: false,
+//			},
+//		}
+//
+//		return e
+//	}
+func newElement(fd *ast.FuncDecl) *element {
+	e := &element{fn: fd.Name.Name, attrs: make(map[string]attr)}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		switch v := kv.Value.(type) {
+		case *ast.BasicLit:
+			if kv.Key.(*ast.Ident).Name == "tag" {
+				e.tag, _ = strconv.Unquote(v.Value)
+			}
+		case *ast.Ident:
+			if kv.Key.(*ast.Ident).Name == "isSelfClosing" {
+				e.void = v.Name == "true"
+			}
+		}
+		return true
+	})
+	if e.tag == "" {
+		return nil
+	}
+	return e
+}
+
+// attrMethod returns the attribute set by fd when it is a method such as:
+//
+//	func (e *htmlA) Href(v string) HTMLA {
+//		e.setAttr("href", v)
+//		return e
+//	}
+//
+// or, since go-app v9.8.0, for the string attributes:
+//
+//	func (e *htmlA) Href(format string, v ...any) HTMLA {
+//		e.setAttr("href", FormatString(format, v...))
+//		return e
+//	}
+func attrMethod(fd *ast.FuncDecl) (string, attr, bool) {
+	params := fd.Type.Params.List
+	if len(params) == 2 && isFormat(params[1]) {
+		params = params[:1]
+	}
+	if len(params) != 1 || len(params[0].Names) != 1 || len(fd.Body.List) != 2 {
+		return "", attr{}, false
+	}
+	typ, ok := params[0].Type.(*ast.Ident)
+	if !ok {
+		return "", attr{}, false
+	}
+	stmt, ok := fd.Body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return "", attr{}, false
+	}
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return "", attr{}, false
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "setAttr" {
+		return "", attr{}, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok {
+		return "", attr{}, false
+	}
+	v := call.Args[1]
+	if f, ok := v.(*ast.CallExpr); ok && len(f.Args) > 0 {
+		if fn, ok := f.Fun.(*ast.Ident); ok && fn.Name == "FormatString" {
+			v = f.Args[0]
+		}
+	}
+	if v, ok := v.(*ast.Ident); !ok || v.Name != params[0].Names[0].Name {
+		return "", attr{}, false
+	}
+	name, _ := strconv.Unquote(lit.Value)
+	return name, attr{Method: fd.Name.Name, Type: typ.Name}, true
+}
+
+// isFormat reports whether p is the v ...any parameter following a format.
+func isFormat(p *ast.Field) bool {
+	e, ok := p.Type.(*ast.Ellipsis)
+	if !ok {
+		return false
+	}
+	id, ok := e.Elt.(*ast.Ident)
+	return ok && (id.Name == "any" || id.Name == "interface{}")
+}
+
+// writeAttrs writes the attributes not in skip, sorted by name.
+func writeAttrs(b *bytes.Buffer, attrs, skip map[string]attr) {
+	var names []string
+	for name := range attrs {
+		if _, ok := skip[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a := attrs[name]
+		fmt.Fprintf(b, "%q: {%q, %q},\n", name, a.Method, a.Type)
+	}
+}
@@ -0,0 +1,376 @@
+// Package html2app converts HTML, such as the mockup a component starts from,
+// into the go-app code building it:
+//
+//	<div class="code-block"><button class="copy-button">Copy code</button></div>
+//
+// becomes
+//
+//	app.Div().Class("code-block").Body(
+//		app.Button().Class("copy-button").Text("Copy code"),
+//	)
+//
+// The elements go-app has no builder for, SVG images among them, are kept as
+// app.Raw. The HTML the browsers would read differently than it is written,
+// such as an attribute given twice, is reported as warnings.
+package html2app
+
+//go:generate go run gen.go
+
+import (
+	"bytes"
+	"fmt"
+	goformat "go/format"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// element is an HTML element go-app has a builder for.
+type element struct {
+	// Func is the go-app function building the element, such as Div.
+	Func string
+
+	// Void tells the element has no content, such as img.
+	Void bool
+
+	// Attrs are the methods setting the attributes of the element, by the
+	// attribute name, besides those of globalAttrs.
+	Attrs map[string]attr
+}
+
+// attr is the method setting an attribute, such as Href.
+type attr struct {
+	Method string
+
+	// Type is the type of the method parameter: string, bool, int, float64
+	// or any.
+	Type string
+}
+
+// enumerated are the attributes go-app sets from a bool that take "true" and
+// "false" values, unlike the boolean attributes whose presence means true.
+var enumerated = map[string]bool{
+	"contenteditable": true,
+	"draggable":       true,
+}
+
+// Warning is a problem of the HTML converted.
+type Warning struct {
+	// Path is the element, such as div.code-block > svg.copy-svg.
+	Path string
+
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// Convert returns the go-app code building the HTML read from r, one
+// app.UI expression per top-level node, followed by a comma when there are
+// several. A full document, starting with a doctype or an html element, is
+// converted from its body.
+func Convert(r io.Reader) (string, []Warning, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var nodes []*html.Node
+	if isDocument(src) {
+		doc, err := html.Parse(bytes.NewReader(src))
+		if err != nil {
+			return "", nil, err
+		}
+		for n := body(doc).FirstChild; n != nil; n = n.NextSibling {
+			nodes = append(nodes, n)
+		}
+	} else {
+		context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		if nodes, err = html.ParseFragment(bytes.NewReader(src), context); err != nil {
+			return "", nil, err
+		}
+	}
+
+	c := converter{}
+	exprs := c.children(nodes, "", false)
+	if len(exprs) == 0 {
+		return "", c.warnings, nil
+	}
+	code, err := format(exprs)
+	return code, c.warnings, err
+}
+
+var documentStart = regexp.MustCompile(`(?i)^\s*(<!--.*?-->\s*)*<(!doctype|html)`)
+
+func isDocument(src []byte) bool {
+	return documentStart.Match(src)
+}
+
+// body returns the body element of doc, which the parser always adds.
+func body(doc *html.Node) *html.Node {
+	for n := doc.FirstChild; n != nil; n = n.NextSibling {
+		if n.DataAtom == atom.Html {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.DataAtom == atom.Body {
+					return c
+				}
+			}
+		}
+	}
+	return &html.Node{}
+}
+
+type converter struct {
+	warnings []Warning
+}
+
+func (c *converter) warn(path, format string, a ...any) {
+	c.warnings = append(c.warnings, Warning{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// children returns the expressions of nodes, the children of the element
+// path. Their text is kept as written when pre is true, inside a pre element.
+func (c *converter) children(nodes []*html.Node, path string, pre bool) []string {
+	var exprs []string
+	for i, n := range nodes {
+		switch n.Type {
+		case html.TextNode:
+			text := n.Data
+			if !pre {
+				text = collapseSpaces(text, i == 0, i == len(nodes)-1)
+			}
+			switch {
+			case text == "":
+			case strings.Contains(text, "\n"):
+				exprs = append(exprs, "app.Text("+rawString(text)+")")
+			default:
+				exprs = append(exprs, "app.Text("+strconv.Quote(text)+")")
+			}
+		case html.ElementNode:
+			if e := c.element(n, path, pre); e != "" {
+				exprs = append(exprs, e)
+			}
+		}
+	}
+	return exprs
+}
+
+var spaces = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// collapseSpaces returns text with its white space collapsed as the browsers
+// display it. The white space at the start or the end of an element, and the
+// one of the line breaks indenting the HTML, is dropped.
+func collapseSpaces(text string, first, last bool) string {
+	if strings.TrimSpace(text) == "" && strings.Contains(text, "\n") {
+		return ""
+	}
+	text = spaces.ReplaceAllString(text, " ")
+	if first {
+		text = strings.TrimLeft(text, " ")
+	}
+	if last {
+		text = strings.TrimRight(text, " ")
+	}
+	return text
+}
+
+// element returns the expression of the element n, a child of the element
+// parent. Its text is kept as written when pre is true.
+func (c *converter) element(n *html.Node, parent string, pre bool) string {
+	path := selector(n)
+	if parent != "" {
+		path = parent + " > " + path
+	}
+	attrs := c.dedup(n, path)
+
+	if n.DataAtom == atom.Script {
+		c.warn(path, "script dropped, go-app does not run the scripts of the components: port it to Go")
+		return ""
+	}
+	e, ok := elements[n.Data]
+	if !ok || n.Namespace != "" {
+		return "app.Raw(" + rawString(outerHTML(n, attrs)) + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("app." + e.Func + "()")
+	for _, a := range attrs {
+		b.WriteString(c.attr(e, a, path))
+	}
+	if e.Void {
+		return b.String()
+	}
+
+	var nodes []*html.Node
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		nodes = append(nodes, ch)
+	}
+	pre = pre || n.DataAtom == atom.Pre || n.DataAtom == atom.Textarea
+	children := c.children(nodes, path, pre)
+	switch {
+	case len(children) == 1 && strings.HasPrefix(children[0], "app.Text("):
+		b.WriteString("." + strings.TrimPrefix(children[0], "app."))
+	case len(children) > 0:
+		b.WriteString(".Body(\n" + strings.Join(children, ",\n") + ",\n)")
+	}
+	return b.String()
+}
+
+// selector returns a selector of the element n, such as svg.copy-svg.
+func selector(n *html.Node) string {
+	s := n.Data
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "id":
+			return s + "#" + a.Val
+		case "class":
+			if classes := strings.Fields(a.Val); len(classes) > 0 {
+				return s + "." + strings.Join(classes, ".")
+			}
+		}
+	}
+	return s
+}
+
+// dedup returns the attributes of n without the duplicates, which the
+// browsers ignore: only the first value of an attribute counts.
+func (c *converter) dedup(n *html.Node, path string) []html.Attribute {
+	var attrs []html.Attribute
+	first := make(map[string]string)
+	for _, a := range n.Attr {
+		key := attrName(a)
+		if v, ok := first[key]; ok {
+			c.warn(path, "duplicate attribute %s=%q dropped, the browsers keep the first one, %s=%q", key, a.Val, key, v)
+			continue
+		}
+		first[key] = a.Val
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+func attrName(a html.Attribute) string {
+	if a.Namespace != "" {
+		return a.Namespace + ":" + a.Key
+	}
+	return a.Key
+}
+
+// attr returns the method call setting the attribute a of the element e.
+func (c *converter) attr(e element, a html.Attribute, path string) string {
+	name := attrName(a)
+	switch {
+	case name == "class":
+		classes := strings.Fields(a.Val)
+		if len(classes) == 0 {
+			return ""
+		}
+		for i, class := range classes {
+			classes[i] = strconv.Quote(class)
+		}
+		return ".Class(" + strings.Join(classes, ", ") + ")"
+
+	case name == "style":
+		var calls []string
+		for _, decl := range strings.Split(a.Val, ";") {
+			if strings.TrimSpace(decl) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(decl, ":")
+			if !ok {
+				return call("Attr", strconv.Quote(name), strconv.Quote(a.Val))
+			}
+			calls = append(calls, call("Style",
+				strconv.Quote(strings.TrimSpace(k)), strconv.Quote(strings.TrimSpace(v))))
+		}
+		return strings.Join(calls, "")
+
+	case strings.HasPrefix(name, "data-"):
+		return call("DataSet", strconv.Quote(strings.TrimPrefix(name, "data-")), strconv.Quote(a.Val))
+
+	case strings.HasPrefix(name, "aria-"):
+		return call("Aria", strconv.Quote(strings.TrimPrefix(name, "aria-")), strconv.Quote(a.Val))
+
+	case strings.HasPrefix(name, "on"):
+		c.warn(path, "%s handler dropped: port it to Go, with the On methods of the element", name)
+		return ""
+	}
+
+	m, ok := e.Attrs[name]
+	if !ok {
+		m, ok = globalAttrs[name]
+	}
+	if !ok {
+		return call("Attr", strconv.Quote(name), strconv.Quote(a.Val))
+	}
+	switch m.Type {
+	case "bool":
+		v := true
+		if enumerated[name] {
+			v = a.Val != "false"
+		}
+		return call(m.Method, strconv.FormatBool(v))
+	case "int":
+		if _, err := strconv.Atoi(a.Val); err == nil {
+			return call(m.Method, a.Val)
+		}
+	case "float64":
+		if _, err := strconv.ParseFloat(a.Val, 64); err == nil {
+			return call(m.Method, a.Val)
+		}
+	default:
+		return call(m.Method, strconv.Quote(a.Val))
+	}
+	// A value the method cannot take, such as width="1em".
+	return call("Attr", strconv.Quote(name), strconv.Quote(a.Val))
+}
+
+func call(method string, args ...string) string {
+	return "." + method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// outerHTML returns the HTML of the element n, with attrs as its attributes.
+func outerHTML(n *html.Node, attrs []html.Attribute) string {
+	var b bytes.Buffer
+	m := *n
+	m.Attr = attrs
+	m.Parent, m.PrevSibling, m.NextSibling = nil, nil, nil
+	// Render only fails on the errors of the writer, and a bytes.Buffer
+	// does not fail.
+	html.Render(&b, &m)
+	return b.String()
+}
+
+// rawString returns the Go string literal of s, a raw one when possible.
+func rawString(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// format returns the gofmt-ed code of exprs.
+func format(exprs []string) (string, error) {
+	const decl = "var _ = "
+	var b strings.Builder
+	for _, e := range exprs {
+		// gofmt indents the lines of a declaration as those of the
+		// expressions of a Body call.
+		out, err := goformat.Source([]byte("package p\n\n" + decl + e + "\n"))
+		if err != nil {
+			return "", err
+		}
+		code := string(out)
+		code = strings.TrimSuffix(code[strings.Index(code, decl)+len(decl):], "\n")
+		if len(exprs) > 1 {
+			code += ","
+		}
+		b.WriteString(code + "\n")
+	}
+	return b.String(), nil
+}
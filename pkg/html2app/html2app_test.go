@@ -0,0 +1,115 @@
+package html2app
+
+import (
+	"go/parser"
+	"strings"
+	"testing"
+)
+
+// copySVG is the icon of the 0B2 copy buttons, which has two class
+// attributes.
+const copySVG = `<svg class="copy-svg" stroke="currentColor" fill="none" stroke-width="2" viewBox="0 0 24 24" stroke-linecap="round" stroke-linejoin="round" class="h-4 w-4" height="1em" width="1em" xmlns="http://www.w3.org/2000/svg"><path d="M16 4h2a2 2 0 0 1 2 2v14a2 2 0 0 1-2 2H6a2 2 0 0 1-2-2V6a2 2 0 0 1 2-2h2"></path><rect x="8" y="2" width="8" height="4" rx="1" ry="1"></rect></svg>`
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		html, want string
+	}{
+		{
+			`<div class="code-block"><button class="copy-button">Copy code</button></div>`,
+			"app.Div().Class(\"code-block\").Body(\n\tapp.Button().Class(\"copy-button\").Text(\"Copy code\"),\n)\n",
+		},
+		{
+			`<p class=" a  b ">Hello <b>you</b> and
+			  <i>me</i>!</p>`,
+			"app.P().Class(\"a\", \"b\").Body(\n\tapp.Text(\"Hello \"),\n\tapp.B().Text(\"you\"),\n\tapp.Text(\" and \"),\n\tapp.I().Text(\"me\"),\n\tapp.Text(\"!\"),\n)\n",
+		},
+		{
+			`<a href="/s/abc" target="_blank" data-id="abc" aria-label="Snippet" style="color: red; margin:0">link</a>`,
+			"app.A().Href(\"/s/abc\").Target(\"_blank\").DataSet(\"id\", \"abc\").Aria(\"label\", \"Snippet\").Style(\"color\", \"red\").Style(\"margin\", \"0\").Text(\"link\")\n",
+		},
+		{
+			`<input type="checkbox" checked disabled="false" maxlength="10" draggable="false" spellcheck="false">`,
+			"app.Input().Type(\"checkbox\").Checked(true).Disabled(true).MaxLength(10).Draggable(false).Attr(\"spellcheck\", \"false\")\n",
+		},
+		{
+			`<img src="a.png" width="1em" height="16" alt="">`,
+			"app.Img().Src(\"a.png\").Attr(\"width\", \"1em\").Height(16).Alt(\"\")\n",
+		},
+		{
+			"<pre><code>a\n  b</code></pre>",
+			"app.Pre().Body(\n\tapp.Code().Text(`a\n  b`),\n)\n",
+		},
+		{
+			`<h1>Title</h1><my-widget size="2"><b>x</b></my-widget>`,
+			"app.H1().Text(\"Title\"),\napp.Raw(`<my-widget size=\"2\"><b>x</b></my-widget>`),\n",
+		},
+		{
+			"<!DOCTYPE html>\n<html><head><title>T</title></head><body>\n  <main>Hi</main>\n</body></html>",
+			"app.Main().Text(\"Hi\")\n",
+		},
+		{"<!-- nothing -->\n", ""},
+	}
+	for _, test := range tests {
+		got, warnings, err := Convert(strings.NewReader(test.html))
+		if err != nil {
+			t.Errorf("Convert(%q): %v", test.html, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Convert(%q) =\n%s\nwant\n%s", test.html, got, test.want)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("Convert(%q): warnings %v", test.html, warnings)
+		}
+		if got != "" {
+			// The code is a Go expression, or a list of expressions.
+			if _, err := parser.ParseExpr("[]app.UI{" + strings.TrimSuffix(got, "\n") + "}"); err != nil {
+				t.Errorf("Convert(%q): invalid Go: %v\n%s", test.html, err, got)
+			}
+		}
+	}
+}
+
+func TestConvertSVG(t *testing.T) {
+	got, warnings, err := Convert(strings.NewReader(`<div class="code-block">` + copySVG + `</div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// go-app has no SVG elements: the image is kept as HTML, without the
+	// class the browsers ignore.
+	want := "app.Div().Class(\"code-block\").Body(\n\tapp.Raw(`" + strings.Replace(copySVG, `class="h-4 w-4" `, "", 1) + "`),\n)\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want one", warnings)
+	}
+	w := warnings[0]
+	if w.Path != "div.code-block > svg.copy-svg" || !strings.Contains(w.Message, `duplicate attribute class="h-4 w-4"`) {
+		t.Errorf("warning = %q", w)
+	}
+}
+
+func TestConvertWarnings(t *testing.T) {
+	tests := []struct {
+		html, want, dropped string
+	}{
+		{`<button id="b" onclick="copy()">Copy</button>`, `button#b: onclick handler dropped`, "copy()"},
+		{`<div><script>document.querySelector("div")</script></div>`, `div > script: script dropped`, "querySelector"},
+		{`<p title="a" title="b">x</p>`, `p: duplicate attribute title="b" dropped`, `"b"`},
+	}
+	for _, test := range tests {
+		code, warnings, err := Convert(strings.NewReader(test.html))
+		if err != nil {
+			t.Errorf("Convert(%q): %v", test.html, err)
+			continue
+		}
+		if len(warnings) != 1 || !strings.HasPrefix(warnings[0].String(), test.want) {
+			t.Errorf("Convert(%q): warnings %v, want %s", test.html, warnings, test.want)
+		}
+		if strings.Contains(code, test.dropped) {
+			t.Errorf("Convert(%q) kept what it warned about:\n%s", test.html, code)
+		}
+	}
+}
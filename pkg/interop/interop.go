@@ -0,0 +1,175 @@
+// Package interop waits for the asynchronous JavaScript APIs of the browser
+// running a go-app app, such as the clipboard or FileReader, from Go:
+//
+//   - Await waits for a Promise to settle;
+//   - Callback waits for the callback of the APIs taking one, such as
+//     DataTransferItem.getAsString, or for an event handler called once,
+//     such as FileReader.onloadend.
+//
+// Both take a context.Context, which bounds the wait, and both release the
+// JavaScript functions they make as soon as the browser is done with them, or
+// once the context is done.
+// The JavaScript errors, the rejections of the promises included, are
+// returned as Error.
+//
+// The waits block until the browser calls back, which it does from the UI
+// goroutine: they must run outside of it, in ctx.Async.
+package interop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// Error is a JavaScript error, such as a TypeError or the DOMException of a
+// FileReader.
+type Error struct {
+	// The name of the error, such as NotAllowedError.
+	Name string
+
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Message)
+}
+
+// ErrorOf returns the error of v: the Error of a JavaScript error object, or
+// of any other value thrown or a promise was rejected with, or the error a Go
+// function recovered from a JavaScript exception. It returns nil for null
+// and undefined, hence nil when a FileReader has no error.
+func ErrorOf(v any) error {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return v
+	case app.Value:
+		if v.IsNull() || v.IsUndefined() {
+			return nil
+		}
+		if v.Type() == app.TypeObject && v.Get("name").Truthy() {
+			return &Error{Name: v.Get("name").String(), Message: v.Get("message").String()}
+		}
+		return &Error{Name: "Error", Message: v.String()}
+	case error:
+		// The exceptions of syscall/js.
+		msg := strings.TrimPrefix(v.Error(), "JavaScript error: ")
+		return &Error{Name: "Error", Message: msg}
+	default:
+		return &Error{Name: "Error", Message: fmt.Sprint(v)}
+	}
+}
+
+// Await waits for the promise p to settle, and returns the value it resolved
+// to, or the Error it was rejected with. It returns the error of ctx should
+// ctx be done first, the settlement of p being lost then. As the await of
+// JavaScript, it returns a value that is not a promise as is.
+func Await(ctx context.Context, p app.Value) (app.Value, error) {
+	if !p.Truthy() || !p.Get("then").Truthy() {
+		return p, nil
+	}
+
+	type result struct {
+		v   app.Value
+		err error
+	}
+	done := make(chan result, 1)
+	var resolve, reject app.Func
+	var once sync.Once
+	// A promise calls one of its callbacks, once: both are released then,
+	// or as Await gives up.
+	release := func() {
+		once.Do(func() {
+			resolve.Release()
+			reject.Release()
+		})
+	}
+	settle := func(r result) {
+		done <- r
+		release()
+	}
+	resolve = app.FuncOf(func(this app.Value, args []app.Value) any {
+		settle(result{v: arg(args)})
+		return nil
+	})
+	reject = app.FuncOf(func(this app.Value, args []app.Value) any {
+		settle(result{err: ErrorOf(arg(args))})
+		return nil
+	})
+	p.Call("then", resolve, reject)
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		release()
+		return app.Undefined(), ctx.Err()
+	}
+}
+
+// Callback is a JavaScript function meant to be called once, the callback of
+// an asynchronous API, whose call Wait waits for. It is a go-app Wrapper,
+// which is passed to the functions of app.Value as is:
+//
+//	cb := interop.NewCallback()
+//	item.Call("getAsString", cb)
+//	args, err := cb.Wait(ctx)
+//
+// The function is released by its first call, or once a wait gives up:
+// later calls are lost, and logged to the console by Go.
+type Callback struct {
+	f        app.Func
+	called   sync.Once
+	released sync.Once
+	args     chan []app.Value
+}
+
+// NewCallback returns a callback waiting for its call.
+func NewCallback() *Callback {
+	c := &Callback{args: make(chan []app.Value, 1)}
+	c.f = app.FuncOf(func(this app.Value, args []app.Value) any {
+		c.called.Do(func() {
+			c.args <- args
+			c.release()
+		})
+		return nil
+	})
+	return c
+}
+
+// JSValue returns the JavaScript function.
+func (c *Callback) JSValue() app.Value {
+	return c.f.JSValue()
+}
+
+// Wait waits for the call of the callback, and returns its arguments. It
+// returns the error of ctx should ctx be done first, in which case the
+// callback is released.
+func (c *Callback) Wait(ctx context.Context) ([]app.Value, error) {
+	select {
+	case args := <-c.args:
+		// The arguments stay for the next waits.
+		c.args <- args
+		return args, nil
+	case <-ctx.Done():
+		c.release()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Callback) release() {
+	c.released.Do(c.f.Release)
+}
+
+// arg returns the first of args, null if there are none.
+func arg(args []app.Value) app.Value {
+	if len(args) == 0 {
+		return app.Null()
+	}
+	return args[0]
+}
@@ -0,0 +1,154 @@
+//go:build js && wasm
+
+package interop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// The tests of this file run in Node.js, with the harness of 0B2-codecopy:
+//
+//	GOARCH=wasm GOOS=js go test -exec "$PWD/../0B2-codecopy/test/go_js_wasm_exec" ./interop/
+
+// consoleErrors replaces console.error, for the calls of the released
+// functions Go logs, and returns a function returning the messages logged
+// since.
+func consoleErrors(t *testing.T) func() []string {
+	console := app.Window().Get("console")
+	orig := console.Get("error")
+	t.Cleanup(func() { console.Set("error", orig) })
+
+	logged := app.Window().Get("Array").New()
+	push := app.Window().Get("Function").New("logged", "return (...args) => logged.push(args.join(' '))")
+	console.Set("error", push.Call("call", nil, logged))
+	return func() []string {
+		msgs := make([]string, logged.Length())
+		for i := range msgs {
+			msgs[i] = logged.Index(i).String()
+		}
+		return msgs
+	}
+}
+
+// pending returns a promise that never settles by itself, along with its
+// resolve function.
+func pending() (p, resolve app.Value) {
+	executor := app.Window().Get("Function").New("resolvers", "return (resolve) => resolvers.push(resolve)")
+	resolvers := app.Window().Get("Array").New()
+	p = app.Window().Get("Promise").New(executor.Call("call", nil, resolvers))
+	return p, resolvers.Index(0)
+}
+
+// later returns the arguments logged to the console once the browser got
+// to settle the promises and run the timers.
+func later(logged func() []string) []string {
+	time.Sleep(20 * time.Millisecond)
+	return logged()
+}
+
+func released(msgs []string) int {
+	n := 0
+	for _, m := range msgs {
+		if strings.Contains(m, "call to released function") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAwaitResolve(t *testing.T) {
+	logged := consoleErrors(t)
+	p := app.Window().Get("Promise").Call("resolve", "settled")
+	v, err := Await(context.Background(), p)
+	if err != nil || v.String() != "settled" {
+		t.Errorf("Await() = %v, %v, want settled", v, err)
+	}
+	if msgs := later(logged); len(msgs) != 0 {
+		t.Errorf("console errors %q", msgs)
+	}
+}
+
+func TestAwaitReject(t *testing.T) {
+	for _, test := range []struct {
+		reason app.Value
+		want   Error
+	}{
+		{app.Window().Get("TypeError").New("not a blob"), Error{Name: "TypeError", Message: "not a blob"}},
+		{app.ValueOf("denied"), Error{Name: "Error", Message: "denied"}},
+	} {
+		p := app.Window().Get("Promise").Call("reject", test.reason)
+		_, err := Await(context.Background(), p)
+		var jsErr *Error
+		if !errors.As(err, &jsErr) || *jsErr != test.want {
+			t.Errorf("Await() error = %#v, want %#v", err, &test.want)
+		}
+	}
+}
+
+func TestAwaitContext(t *testing.T) {
+	logged := consoleErrors(t)
+
+	p, resolve := pending()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := Await(ctx, p); err != context.DeadlineExceeded {
+		t.Errorf("Await() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	q, _ := pending()
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := Await(ctx, q); err != context.Canceled {
+		t.Errorf("Await() error = %v, want %v", err, context.Canceled)
+	}
+
+	// Settled too late, the promise calls the function Await released.
+	resolve.Invoke("late")
+	if n := released(later(logged)); n != 1 {
+		t.Errorf("%d calls to released functions, want 1", n)
+	}
+}
+
+func TestCallbackCall(t *testing.T) {
+	logged := consoleErrors(t)
+	c := NewCallback()
+	app.Window().Call("setTimeout", c, 0, "first")
+	args, err := c.Wait(context.Background())
+	if err != nil || len(args) != 1 || args[0].String() != "first" {
+		t.Fatalf("Wait() = %v, %v, want the arguments", args, err)
+	}
+
+	// Released by its call, the callback does not take another one.
+	app.Window().Call("setTimeout", c, 0, "second")
+	if n := released(later(logged)); n != 1 {
+		t.Errorf("%d calls to released functions, want 1", n)
+	}
+	if args, _ := c.Wait(context.Background()); args[0].String() != "first" {
+		t.Errorf("Wait() = %v after a second call, want the first arguments", args)
+	}
+}
+
+func TestCallbackContext(t *testing.T) {
+	logged := consoleErrors(t)
+	c := NewCallback()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Given up on, the callback is released.
+	app.Window().Call("setTimeout", c, 0, "late")
+	if n := released(later(logged)); n != 1 {
+		t.Errorf("%d calls to released functions, want 1", n)
+	}
+}
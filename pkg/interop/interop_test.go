@@ -0,0 +1,66 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+func TestErrorOf(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{errors.New("JavaScript error: Read permission denied."), "Error: Read permission denied."},
+		{"rejected", "Error: rejected"},
+		{&Error{Name: "NotReadableError", Message: "The file could not be read."}, "NotReadableError: The file could not be read."},
+	}
+	for _, test := range tests {
+		err := ErrorOf(test.v)
+		var jsErr *Error
+		if !errors.As(err, &jsErr) {
+			t.Errorf("ErrorOf(%#v) = %#v, want an Error", test.v, err)
+			continue
+		}
+		if got := err.Error(); got != test.want {
+			t.Errorf("ErrorOf(%#v) = %q, want %q", test.v, got, test.want)
+		}
+	}
+
+	// No error, as that of a FileReader that succeeded.
+	for _, v := range []any{nil, app.Null(), app.Undefined()} {
+		if err := ErrorOf(v); err != nil {
+			t.Errorf("ErrorOf(%#v) = %v, want nil", v, err)
+		}
+	}
+}
+
+func TestAwait(t *testing.T) {
+	// Not a promise, as all the values on the server.
+	v := app.Null()
+	got, err := Await(context.Background(), v)
+	if err != nil || !got.Equal(v) {
+		t.Errorf("Await(null) = %v, %v, want the value", got, err)
+	}
+}
+
+func TestCallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewCallback().Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait error = %v, want %v", err, context.Canceled)
+	}
+
+	// The call, as the function makes it.
+	c := NewCallback()
+	args := []app.Value{app.Null()}
+	c.args <- args
+	for i := 0; i < 2; i++ {
+		got, err := c.Wait(context.Background())
+		if err != nil || len(got) != 1 {
+			t.Errorf("wait %d: Wait() = %v, %v, want the arguments", i, got, err)
+		}
+	}
+}
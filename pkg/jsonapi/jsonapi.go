@@ -0,0 +1,158 @@
+// Package jsonapi answers, and calls, the small JSON APIs of the demo
+// servers, such as those of the 0B2D snippets and of pkg/upload.
+//
+// An API answers its errors as {"error": "<message>"}, with the status code
+// of the sentinel error they wrap, and Do turns them back into errors that
+// wrap the same sentinel, so that errors.Is works the same on both sides.
+package jsonapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// API describes the errors of an API.
+type API struct {
+	// The name of the API, such as "snippet API", which prefixes the
+	// messages of the errors logged and of those Do returns.
+	Name string
+
+	// Statuses are the status codes the errors are answered with, by the
+	// sentinel error they wrap. The other errors are logged, and answered
+	// with a 500 Internal Server Error.
+	Statuses []Status
+}
+
+// Status associates a sentinel error with its status code.
+type Status struct {
+	Err  error
+	Code int
+}
+
+// WriteError answers err as JSON, with the status code of the sentinel error
+// it wraps.
+func (a API) WriteError(w http.ResponseWriter, err error) {
+	for _, s := range a.Statuses {
+		if errors.Is(err, s.Err) {
+			Error(w, s.Code, err.Error())
+			return
+		}
+	}
+	log.Printf("%s: %v", a.Name, err)
+	Error(w, http.StatusInternalServerError, "internal error")
+}
+
+// Do sends a request with the body of type ctype, if any, to the path p of
+// the API server at base, and decodes the JSON answered into v, unless v is
+// nil. base defaults to the URL of the page, in the web browser; p may have a
+// query. The errors answered are returned as those WriteError was given.
+func (a API) Do(ctx context.Context, base, method, p, ctype string, body []byte, v any) error {
+	u, err := a.url(base, p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if ctype != "" {
+		req.Header.Set("Content-Type", ctype)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		var e apiError
+		json.NewDecoder(res.Body).Decode(&e)
+		return a.errorOf(res, e.Error)
+	}
+	if v == nil {
+		_, err = io.Copy(io.Discard, res.Body)
+		return err
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// errorOf returns the error answered in res with msg: the sentinel error of
+// its status code, along with the reason msg gives.
+func (a API) errorOf(res *http.Response, msg string) error {
+	for _, s := range a.Statuses {
+		if s.Code != res.StatusCode {
+			continue
+		}
+		if reason, ok := strings.CutPrefix(msg, s.Err.Error()+": "); ok {
+			return Wrap(s.Err, reason)
+		}
+		return s.Err
+	}
+	return fmt.Errorf("%s: %s: %s", a.Name, res.Status, msg)
+}
+
+// url returns the URL of the path p of the server at base.
+func (a API) url(base, p string) (string, error) {
+	if base == "" {
+		if app.IsServer {
+			return "", fmt.Errorf("%s: no server URL", a.Name)
+		}
+		base = app.Window().URL().String()
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(p)
+	if err != nil {
+		return "", err
+	}
+	return u.ResolveReference(ref).String(), nil
+}
+
+// Error answers msg as the JSON error of status code.
+func Error(w http.ResponseWriter, code int, msg string) {
+	WriteJSON(w, code, apiError{Error: msg})
+}
+
+// WriteJSON answers v as JSON, with status code.
+func WriteJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiError is the body of the error responses.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// Wrap returns the error of sentinel for reason, such as "invalid snippet: no
+// code", which wraps sentinel.
+func Wrap(sentinel error, reason string) error {
+	return &reasonError{sentinel: sentinel, reason: reason}
+}
+
+type reasonError struct {
+	sentinel error
+	reason   string
+}
+
+func (e *reasonError) Error() string {
+	return e.sentinel.Error() + ": " + e.reason
+}
+
+func (e *reasonError) Unwrap() error {
+	return e.sentinel
+}
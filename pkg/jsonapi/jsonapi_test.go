@@ -0,0 +1,76 @@
+package jsonapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var (
+	errNotFound = errors.New("thing not found")
+	errInvalid  = errors.New("invalid thing")
+)
+
+var api = API{
+	Name: "thing API",
+	Statuses: []Status{
+		{errNotFound, http.StatusNotFound},
+		{errInvalid, http.StatusBadRequest},
+	},
+}
+
+func TestWrap(t *testing.T) {
+	err := Wrap(errInvalid, "too large")
+	if !errors.Is(err, errInvalid) || errors.Is(err, errNotFound) {
+		t.Errorf("Wrap() = %v, want an error wrapping errInvalid only", err)
+	}
+	if got, want := err.Error(), "invalid thing: too large"; got != want {
+		t.Errorf("Wrap() = %q, want %q", got, want)
+	}
+}
+
+func TestAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if r.Header.Get("Content-Type") != "text/plain" || r.URL.Query().Get("q") != "1" {
+				Error(w, http.StatusBadRequest, "unexpected request")
+				return
+			}
+			WriteJSON(w, http.StatusOK, map[string]int{"n": 1})
+		case "/missing":
+			api.WriteError(w, errNotFound)
+		case "/invalid":
+			api.WriteError(w, Wrap(errInvalid, "too large"))
+		default:
+			api.WriteError(w, errors.New("disk full"))
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	var v struct{ N int }
+	if err := api.Do(ctx, srv.URL, http.MethodPost, "/ok?q=1", "text/plain", []byte("body"), &v); err != nil || v.N != 1 {
+		t.Errorf("Do(/ok) = %+v, %v", v, err)
+	}
+	if err := api.Do(ctx, srv.URL, http.MethodGet, "/ok?q=1", "text/plain", nil, nil); err != nil {
+		t.Errorf("Do(/ok) without value: %v", err)
+	}
+	if err := api.Do(ctx, srv.URL, http.MethodGet, "/missing", "", nil, nil); err != errNotFound {
+		t.Errorf("Do(/missing) = %v, want %v", err, errNotFound)
+	}
+	err := api.Do(ctx, srv.URL, http.MethodGet, "/invalid", "", nil, nil)
+	if !errors.Is(err, errInvalid) || err.Error() != "invalid thing: too large" {
+		t.Errorf("Do(/invalid) = %v, want the reason", err)
+	}
+	err = api.Do(ctx, srv.URL, http.MethodGet, "/broken", "", nil, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "thing API: 500 Internal Server Error: internal error") {
+		t.Errorf("Do(/broken) = %v, want the internal error", err)
+	}
+	if err := api.Do(ctx, "", http.MethodGet, "/ok", "", nil, nil); err == nil {
+		t.Error("Do() without server URL on the server-side: no error")
+	}
+}
@@ -0,0 +1,41 @@
+// Package jsonapitest checks the errors a JSON API answers, the way the tests
+// of the 0B2D snippets and of the uploads do.
+package jsonapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Request is a request an API answers with an error.
+type Request struct {
+	Method, Path, Body string
+
+	// The status code of the error.
+	Want int
+}
+
+// Errors checks that h answers each of reqs with its status code and a JSON
+// error message.
+func Errors(t *testing.T, h http.Handler, reqs []Request) {
+	t.Helper()
+	for _, r := range reqs {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(r.Method, r.Path, strings.NewReader(r.Body)))
+		if w.Code != r.Want {
+			t.Errorf("%s %s = %d, want %d", r.Method, r.Path, w.Code, r.Want)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s %s: Content-Type = %q", r.Method, r.Path, ct)
+		}
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&e); err != nil || e.Error == "" {
+			t.Errorf("%s %s: no error message, %v", r.Method, r.Path, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+const (
+	// WasmFile is the path of the app, relative to the demo directory.
+	WasmFile = "web/app.wasm"
+
+	// ImmutableCacheControl is the Cache-Control header of the web resources
+	// requested with the current version, which never change.
+	ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+	// UncompressedLengthHeader carries the size of a resource served from its
+	// precompressed variant, which the app loader needs to show its progress.
+	UncompressedLengthHeader = "X-Uncompressed-Length"
+)
+
+// encodings are the precompressed variants of the web resources, in order of
+// preference: app.wasm.br is served over app.wasm.gz.
+var encodings = []struct {
+	name string
+	ext  string
+}{
+	{name: "br", ext: ".br"},
+	{name: "gzip", ext: ".gz"},
+}
+
+// WasmVersion returns the content hash of the app.wasm file at name in fsys.
+// Used as the version of the app, it changes exactly when the code does, and
+// not on every build or restart of the server.
+func WasmVersion(fsys fs.FS, name string) (string, error) {
+	return FileVersion(fsys, name)
+}
+
+// FileVersion returns the content hash of the file name in fsys, which
+// versions the web resources other than app.wasm, as in
+// /web/styles.css?v=<version>.
+func FileVersion(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// Assets returns the resource provider serving the web directory of fsys,
+// with the resources referenced under prefix. app.wasm is referenced along
+// with version, as in /web/app.wasm?v=<version>, and the requests carrying
+// the current version, or for the other resources their FileVersion, are
+// served with long-lived immutable cache headers.
+// Resources are served from their up to date .br or .gz variant, when there
+// is one that the browser accepts.
+func Assets(fsys fs.FS, prefix, version string) app.ResourceProvider {
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		prefix = "/" + prefix
+	}
+	return assets{fsys: fsys, prefix: prefix, version: version}
+}
+
+type assets struct {
+	fsys    fs.FS
+	prefix  string
+	version string
+}
+
+func (a assets) Package() string {
+	return a.prefix
+}
+
+func (a assets) Static() string {
+	return a.prefix
+}
+
+func (a assets) AppWASM() string {
+	if a.version == "" {
+		return a.prefix + "/" + WasmFile
+	}
+	return a.prefix + "/" + WasmFile + "?v=" + a.version
+}
+
+func (a assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// go-app serves the legacy /app.wasm path by replacing the request path
+	// with AppWASM(), query included.
+	p, _, _ := strings.Cut(r.URL.Path, "?")
+	name := strings.TrimPrefix(path.Clean(p), "/")
+	if !strings.HasPrefix(name, "web/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, info, encoding, err := a.open(w.Header(), name, r.Header.Get("Accept-Encoding"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	if a.current(name, r.URL.Query().Get("v")) {
+		w.Header().Set("Cache-Control", ImmutableCacheControl)
+	}
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+	if encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
+
+// current reports whether version is the current one of the resource name:
+// the app version for app.wasm, its FileVersion otherwise.
+func (a assets) current(name, version string) bool {
+	switch {
+	case version == "":
+		return false
+	case name == WasmFile:
+		return version == a.version
+	}
+	v, err := FileVersion(a.fsys, name)
+	return err == nil && version == v
+}
+
+// open opens the resource name, or its precompressed variant for the first
+// encoding accepted. Variants older than the resource are ignored, so that a
+// stale app.wasm.br is never served for a rebuilt app.wasm.
+func (a assets) open(header http.Header, name, accept string) (fs.File, fs.FileInfo, string, error) {
+	info, err := fs.Stat(a.fsys, name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if info.IsDir() {
+		return nil, nil, "", errors.New(name + " is a directory")
+	}
+
+	for _, e := range encodings {
+		vinfo, err := fs.Stat(a.fsys, name+e.ext)
+		if err != nil || vinfo.ModTime().Before(info.ModTime()) {
+			continue
+		}
+		header.Set("Vary", "Accept-Encoding")
+		if !accepts(accept, e.name) {
+			continue
+		}
+		f, err := a.fsys.Open(name + e.ext)
+		if err != nil {
+			continue
+		}
+		header.Set(UncompressedLengthHeader, strconv.FormatInt(info.Size(), 10))
+		return f, vinfo, e.name, nil
+	}
+
+	f, err := a.fsys.Open(name)
+	return f, info, "", err
+}
+
+// accepts reports whether the Accept-Encoding header accepts encoding.
+func accepts(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), encoding) {
+			continue
+		}
+		params = strings.TrimSpace(params)
+		if !strings.HasPrefix(params, "q=") {
+			return true
+		}
+		v, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+		return err == nil && v > 0
+	}
+	return false
+}
+
+// webFS returns the file system holding the web directory: web, or the
+// working directory when nil.
+func webFS(web fs.FS) fs.FS {
+	if web == nil {
+		return os.DirFS(".")
+	}
+	return web
+}
+
+// versionAssets sets the version of h to the content hash of app.wasm, and
+// serves its resources from the web directory of web under prefix, with the
+// paths of the web resources h references, such as its styles and icons,
+// versioned. Handlers with their own resource provider are left as is.
+func versionAssets(h *app.Handler, web fs.FS, prefix string) {
+	fsys := webFS(web)
+	if h.Version == "" {
+		// Without app.wasm, as in tests, go-app picks a version per start.
+		h.Version, _ = WasmVersion(fsys, WasmFile)
+	}
+	if h.Resources == nil {
+		h.Resources = Assets(fsys, prefix, h.Version)
+		for _, paths := range [][]string{h.Styles, h.Scripts, h.CacheableResources} {
+			for i, p := range paths {
+				paths[i] = versionPath(fsys, p)
+			}
+		}
+		for _, p := range []*string{&h.Image, &h.Icon.Default, &h.Icon.Large, &h.Icon.SVG, &h.Icon.AppleTouch} {
+			*p = versionPath(fsys, *p)
+		}
+	}
+	if h.WasmContentLengthHeader == "" {
+		h.WasmContentLengthHeader = UncompressedLengthHeader
+	}
+}
+
+// versionPath returns the path p of a web resource of fsys, such as
+// /web/styles.css, along with its FileVersion. Other paths, such as remote
+// URLs, and those already carrying a query are returned as is.
+func versionPath(fsys fs.FS, p string) string {
+	if !strings.HasPrefix(p, "/web/") || strings.Contains(p, "?") {
+		return p
+	}
+	v, err := FileVersion(fsys, strings.TrimPrefix(p, "/"))
+	if err != nil {
+		return p
+	}
+	return p + "?v=" + v
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWasmVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/app.wasm": {Data: []byte("wasm")},
+		"b/app.wasm": {Data: []byte("wasm")},
+		"c/app.wasm": {Data: []byte("rebuilt wasm")},
+	}
+	version := func(name string) string {
+		v, err := WasmVersion(fsys, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if a, b := version("a/app.wasm"), version("b/app.wasm"); a != b {
+		t.Errorf("same content versions = %s, %s", a, b)
+	}
+	if a, c := version("a/app.wasm"), version("c/app.wasm"); a == c {
+		t.Errorf("different content versions are both %s", a)
+	}
+	if _, err := WasmVersion(fsys, "missing/app.wasm"); err == nil {
+		t.Error("missing app.wasm has a version")
+	}
+}
+
+func TestAssets(t *testing.T) {
+	built := time.Now()
+	fsys := fstest.MapFS{
+		"web/app.wasm":       {Data: []byte("wasm"), ModTime: built},
+		"web/app.wasm.br":    {Data: []byte("br"), ModTime: built},
+		"web/app.wasm.gz":    {Data: []byte("gz"), ModTime: built},
+		"web/styles.css":     {Data: []byte("body {}"), ModTime: built},
+		"web/styles.css.gz":  {Data: []byte("stale"), ModTime: built.Add(-time.Hour)},
+		"web/icons/icon.png": {Data: []byte("png"), ModTime: built},
+		"main.go":            {Data: []byte("package main"), ModTime: built},
+	}
+	a := Assets(fsys, "demo", "v1")
+	if got := a.AppWASM(); got != "/demo/web/app.wasm?v=v1" {
+		t.Errorf("AppWASM() = %s", got)
+	}
+	h := a.(http.Handler)
+	styles, err := FileVersion(fsys, "web/styles.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		accept   string
+		status   int
+		body     string
+		encoding string
+		cache    string
+	}{
+		{path: "/web/app.wasm?v=v1", accept: "gzip, br", status: 200, body: "br", encoding: "br", cache: ImmutableCacheControl},
+		{path: "/web/app.wasm?v=v1", accept: "gzip, br;q=0", status: 200, body: "gz", encoding: "gzip", cache: ImmutableCacheControl},
+		{path: "/web/app.wasm?v=v1", status: 200, body: "wasm", cache: ImmutableCacheControl},
+		{path: "/web/app.wasm?v=v0", accept: "br", status: 200, body: "br", encoding: "br"},
+		{path: "/web/app.wasm", status: 200, body: "wasm"},
+		{path: "/web/styles.css", accept: "gzip", status: 200, body: "body {}"},
+		{path: "/web/styles.css?v=" + styles, status: 200, body: "body {}", cache: ImmutableCacheControl},
+		{path: "/web/styles.css?v=v1", status: 200, body: "body {}"},
+		{path: "/web/icons/", status: 404},
+		{path: "/web/missing.css", status: 404},
+		{path: "/main.go", status: 404},
+		{path: "/web/../main.go", status: 404},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		if test.accept != "" {
+			req.Header.Set("Accept-Encoding", test.accept)
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+
+		if res.Code != test.status {
+			t.Errorf("%s: status = %d, want %d", test.path, res.Code, test.status)
+			continue
+		}
+		if test.status != 200 {
+			continue
+		}
+		if got := res.Body.String(); got != test.body {
+			t.Errorf("%s (%s): body = %q, want %q", test.path, test.accept, got, test.body)
+		}
+		if got := res.Header().Get("Content-Encoding"); got != test.encoding {
+			t.Errorf("%s (%s): Content-Encoding = %q, want %q", test.path, test.accept, got, test.encoding)
+		}
+		if got := res.Header().Get("Cache-Control"); got != test.cache {
+			t.Errorf("%s: Cache-Control = %q, want %q", test.path, got, test.cache)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/web/app.wasm?v=v1", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	if got := res.Header().Values("Vary"); len(got) != 1 {
+		t.Errorf("Vary = %q, want a single Accept-Encoding", got)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":           "application/wasm",
+		"Vary":                   "Accept-Encoding",
+		UncompressedLengthHeader: "4",
+	} {
+		if got := res.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestAccepts(t *testing.T) {
+	tests := []struct {
+		header   string
+		encoding string
+		want     bool
+	}{
+		{header: "gzip, deflate, br", encoding: "br", want: true},
+		{header: "gzip, deflate", encoding: "br", want: false},
+		{header: "GZIP", encoding: "gzip", want: true},
+		{header: "br;q=0.5, gzip", encoding: "br", want: true},
+		{header: "br;q=0, gzip", encoding: "br", want: false},
+		{header: "", encoding: "gzip", want: false},
+	}
+	for _, test := range tests {
+		if got := accepts(test.header, test.encoding); got != test.want {
+			t.Errorf("accepts(%q, %q) = %v, want %v", test.header, test.encoding, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build !wasm
+
+package server
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/akrylysov/algnhsa"
+)
+
+// runMode returns how Run serves the app: it generates the static website
+// when the -static flag is set, serves the AWS Lambda events when executed by
+// AWS Lambda, and listens otherwise.
+func runMode() mode {
+	switch {
+	case *staticFlag:
+		return modeStatic
+	case os.Getenv(LambdaEnv) != "":
+		return modeLambda
+	default:
+		return modeListen
+	}
+}
+
+// ServeLambda serves h the API Gateway v1, API Gateway v2 and ALB events AWS
+// Lambda invokes the function with, and never returns. Every response body is
+// base64 encoded, as app.wasm and the precompressed resources are binary;
+// API Gateway REST APIs must list */* as binary media type for it to be
+// decoded.
+func ServeLambda(h http.Handler) {
+	log.Println("Serving AWS Lambda events")
+	algnhsa.ListenAndServe(h, &algnhsa.Options{
+		BinaryContentTypes: []string{"*/*"},
+	})
+}
@@ -0,0 +1,12 @@
+package server
+
+import "net/http"
+
+// In the web browser, Run launches the app before picking how to serve it,
+// and AWS Lambda is left out of app.wasm.
+func runMode() mode {
+	return modeListen
+}
+
+// ServeLambda does nothing in the web browser.
+func ServeLambda(h http.Handler) {}
@@ -0,0 +1,77 @@
+package lambdatest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Response is the response payload of a function served through algnhsa, in
+// the API Gateway v1, API Gateway v2 or ALB format.
+type Response struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+// ParseResponse parses a response payload.
+func ParseResponse(payload []byte) (Response, error) {
+	var r Response
+	err := json.Unmarshal(payload, &r)
+	return r, err
+}
+
+// Header returns the first value of the response header key, whether the
+// response has single or multi-value headers.
+func (r Response) Header(key string) string {
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	for k, v := range r.MultiValueHeaders {
+		if strings.EqualFold(k, key) && len(v) != 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// Content returns the response body, decoded when base64 encoded.
+func (r Response) Content() ([]byte, error) {
+	if r.IsBase64Encoded {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// WithPath returns the event, a recorded API Gateway v1, API Gateway v2 or
+// ALB event, requesting path instead.
+func WithPath(event []byte, path string) ([]byte, error) {
+	var e map[string]any
+	if err := json.Unmarshal(event, &e); err != nil {
+		return nil, err
+	}
+	ctx, _ := e["requestContext"].(map[string]any)
+
+	switch {
+	case e["version"] == "2.0":
+		e["rawPath"] = path
+		if h, ok := ctx["http"].(map[string]any); ok {
+			h["path"] = path
+		}
+	case ctx["elb"] != nil:
+		e["path"] = path
+	case ctx["accountId"] != nil:
+		e["path"] = path
+		stage, _ := ctx["stage"].(string)
+		ctx["path"] = "/" + stage + path
+		e["pathParameters"] = map[string]any{"proxy": strings.TrimPrefix(path, "/")}
+	default:
+		return nil, errors.New("not an API Gateway or ALB event")
+	}
+	return json.Marshal(e)
+}
@@ -0,0 +1,100 @@
+// Package lambdatest invokes an HTTP handler in-process, the way AWS Lambda
+// invokes a demo: the handler is served by server.ServeLambda, through
+// algnhsa, and invoked with events such as the API Gateway v1, API Gateway v2
+// and ALB events the demos record in testdata.
+//
+// aws-lambda-go serves the function over net/rpc, in the mode of the go1.x
+// runtime, when _LAMBDA_SERVER_PORT is set, so that the events reach the
+// algnhsa handler without a Lambda Runtime API nor a separate process.
+package lambdatest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/rpc"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+// FunctionARN is the ARN the invocations are made with.
+const FunctionARN = "arn:aws:lambda:us-east-1:123456789012:function:demo"
+
+// Timeout is the deadline of an invocation.
+const Timeout = 10 * time.Second
+
+var (
+	// mu serializes the invocations, each with its own handler.
+	mu      sync.Mutex
+	handler http.Handler
+	lastID  int
+
+	// The function is served once per process, as aws-lambda-go registers it
+	// with the default RPC server.
+	start    sync.Once
+	client   *rpc.Client
+	startErr error
+)
+
+// Invoke invokes h with the event payload, and returns its response payload.
+func Invoke(h http.Handler, event []byte) ([]byte, error) {
+	start.Do(func() {
+		client, startErr = serve()
+	})
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	handler = h
+	lastID++
+
+	deadline := time.Now().Add(Timeout)
+	req := &messages.InvokeRequest{
+		Payload:            event,
+		RequestId:          strconv.Itoa(lastID),
+		Deadline:           messages.InvokeRequest_Timestamp{Seconds: deadline.Unix(), Nanos: int64(deadline.Nanosecond())},
+		InvokedFunctionArn: FunctionARN,
+	}
+	var res messages.InvokeResponse
+	if err := client.Call("Function.Invoke", req, &res); err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("%s: %s", res.Error.Type, res.Error.Message)
+	}
+	return res.Payload, nil
+}
+
+// serve serves the current handler through server.ServeLambda, on a free
+// local port, and returns the client connected to it.
+func serve() (*rpc.Client, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+	if err := os.Setenv("_LAMBDA_SERVER_PORT", port); err != nil {
+		return nil, err
+	}
+
+	go server.ServeLambda(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Called while Invoke holds mu.
+		handler.ServeHTTP(w, r)
+	}))
+
+	for end := time.Now().Add(Timeout); time.Now().Before(end); time.Sleep(10 * time.Millisecond) {
+		if c, err := rpc.Dial("tcp", "localhost:"+port); err == nil {
+			return c, nil
+		}
+	}
+	return nil, errors.New("lambdatest: the function did not start")
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// LogRequests wraps h so that every request is logged along with its response
+// status and duration.
+func LogRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rw, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), rw.status, time.Since(start))
+	})
+}
+
+// statusWriter records the status code written to a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets the handlers stream their responses, such as server-sent events.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,234 @@
+// Package server is the bootstrap shared by the go-app demos. It associates
+// the demo components with their paths, launches the app when executed in the
+// web browser, and otherwise serves it over HTTP with request logging, a
+// health endpoint and graceful shutdown, or through AWS Lambda.
+//
+// Run listens on DefaultAddr unless told otherwise by the -addr or -port
+// flag, or the PORT environment variable, which Deta Space sets. Besides the
+// app, it answers HealthPath and the Endpoints of the demo, and shuts down
+// gracefully on SIGINT and SIGTERM. Executed by AWS Lambda, with LambdaEnv
+// set, it serves the Lambda events through algnhsa instead (see ServeLambda),
+// so that the same demo deploys unchanged locally, to Deta Space or to AWS
+// Lambda. A route path starting with ^ is a regular expression, such as the
+// /s/{id} pages of the 0B2D-codecopy demo (see Route).
+//
+// With -static, as make static does, Run exports the demo as a static website
+// into ../dist/<demo> instead, ready for hosts such as GitHub Pages: -dist
+// changes the output directory, and -base the path the site is served from,
+// /<demo> by default (see GenerateStatic).
+//
+// The app version is the content hash of web/app.wasm, so that the update
+// notification of the apps only shows when the code changed, not on every
+// restart. app.wasm is loaded as /web/app.wasm?v=<version> and served with
+// immutable cache headers, as are the styles, scripts, icons and cacheable
+// resources of the app.Handler under /web/, referenced along with their own
+// content hash; the fonts are not versioned, and neither are the resources
+// the components reference. app.wasm is served from its precompressed
+// app.wasm.br or app.wasm.gz variant when the browser accepts it and the
+// variant is not older (see Assets).
+//
+// Built with -tags embed, as make embed does, a demo carries its web
+// directory, app.wasm included, and runs from any directory: its embed.go
+// hands the embedded files to Config.Web. go:embed leaves out the symbolic
+// links, so the web files are real files.
+package server
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+const (
+	// DefaultAddr is the address listened on when neither the -addr and -port
+	// flags nor the PORT environment variable are set.
+	DefaultAddr = ":8000"
+
+	// HealthPath is the path of the health endpoint.
+	HealthPath = "/healthz"
+
+	// ShutdownTimeout is how long in-flight requests are given to complete
+	// once a SIGINT or SIGTERM is received.
+	ShutdownTimeout = 5 * time.Second
+
+	// LambdaEnv is the environment variable AWS Lambda sets for the function
+	// runtime. When it is set, Run serves the AWS Lambda events instead of
+	// listening.
+	LambdaEnv = "AWS_LAMBDA_RUNTIME_API"
+)
+
+var (
+	addrFlag = flag.String("addr", "", "address to listen on, e.g. localhost:8000")
+	portFlag = flag.String("port", "", "port to listen on, shorthand for -addr :<port>")
+)
+
+// Route associates a component with a path, as app.Route does: every
+// navigation to Path mounts a new component of the type of Compo, with its
+// fields zero.
+//
+// A Path starting with ^ is a regular expression, such as ^/s/[^/]+$, that
+// the paths of its pages match, as with app.RouteWithRegexp. Such routes have
+// no page in the static website.
+type Route struct {
+	Path  string
+	Compo app.Composer
+}
+
+// Endpoint associates an HTTP handler with a path pattern of the server, such
+// as an API the app calls. Endpoints only exist on the server-side: neither
+// the static website nor the routes of the app have them.
+type Endpoint struct {
+	Pattern string
+	Handler http.Handler
+}
+
+// Config describes a demo: its routes and the handler that serves it.
+type Config struct {
+	// The routes of the app. They are registered on both client and
+	// server-side.
+	Routes []Route
+
+	// The handler that serves the client and all its required resources.
+	Handler *app.Handler
+
+	// The endpoints served next to the app.
+	Endpoints []Endpoint
+
+	// The address listened on when no flag or PORT environment variable is
+	// set. Defaults to DefaultAddr.
+	Addr string
+
+	// The file system holding the web directory the resources are served
+	// from, such as an embed.FS with web/ embedded, which lets the server run
+	// from any directory. Defaults to the working directory.
+	Web fs.FS
+}
+
+// mode is how Run serves the app.
+type mode int
+
+const (
+	modeListen mode = iota
+	modeStatic
+	modeLambda
+)
+
+// Run starts the demo described by cfg. In the web browser it launches the
+// app and never returns. On the server-side it serves the app until a SIGINT
+// or SIGTERM is received, on the address picked by ListenAddr, and exits on
+// failure. When the -static flag is set, it generates the static website of
+// the app instead; when executed by AWS Lambda, it serves the Lambda events.
+// The same demo thus runs unchanged locally, on Deta Space, which sets PORT,
+// and on AWS Lambda.
+func Run(cfg Config) {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	// GenerateStatic registers the routes itself: go-app would list the
+	// regular expression ones twice.
+	if runMode() == modeStatic {
+		generateStatic(cfg)
+		return
+	}
+
+	h := NewHandler(cfg)
+	switch runMode() {
+	case modeLambda:
+		ServeLambda(h)
+	default:
+		if err := ListenAndServe(ListenAddr(cfg.Addr), h); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// NewHandler registers the routes of cfg and launches the app when executed
+// in the web browser. On the server-side, it returns the handler serving the
+// app along with the health endpoint and the endpoints of cfg, with every
+// request logged.
+func NewHandler(cfg Config) http.Handler {
+	registerRoutes(cfg.Routes)
+
+	// When executed on the server-side, RunWhenOnBrowser() does nothing, which
+	// lets room for the server implementation below.
+	app.RunWhenOnBrowser()
+	versionAssets(cfg.Handler, cfg.Web, "")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthPath, health)
+	for _, e := range cfg.Endpoints {
+		mux.Handle(e.Pattern, e.Handler)
+	}
+	mux.Handle("/", cfg.Handler)
+	return LogRequests(mux)
+}
+
+// ListenAddr returns the address to listen on, picked in order from the -addr
+// flag, the -port flag, the PORT environment variable, def and DefaultAddr.
+func ListenAddr(def string) string {
+	switch {
+	case *addrFlag != "":
+		return *addrFlag
+	case *portFlag != "":
+		return ":" + *portFlag
+	case os.Getenv("PORT") != "":
+		return ":" + os.Getenv("PORT")
+	case def != "":
+		return def
+	default:
+		return DefaultAddr
+	}
+}
+
+// ListenAndServe serves h on addr until a SIGINT or SIGTERM is received, then
+// shuts the server down gracefully.
+func ListenAndServe(addr string, h http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := &http.Server{Addr: addr, Handler: h}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.ListenAndServe()
+	}()
+	log.Println("Listening on http://" + addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	return s.Shutdown(ctx)
+}
+
+func registerRoutes(routes []Route) {
+	for _, r := range routes {
+		if r.isRegexp() {
+			app.RouteWithRegexp(r.Path, r.Compo)
+			continue
+		}
+		app.Route(r.Path, r.Compo)
+	}
+}
+
+func (r Route) isRegexp() bool {
+	return strings.HasPrefix(r.Path, "^")
+}
+
+func health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		addr string
+		port string
+		env  string
+		def  string
+		want string
+	}{
+		{name: "default", want: DefaultAddr},
+		{name: "config", def: ":8080", want: ":8080"},
+		{name: "env", env: "9000", def: ":8080", want: ":9000"},
+		{name: "port flag", port: "9001", env: "9000", want: ":9001"},
+		{name: "addr flag", addr: "localhost:9002", port: "9001", want: "localhost:9002"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			*addrFlag, *portFlag = test.addr, test.port
+			defer func() { *addrFlag, *portFlag = "", "" }()
+			t.Setenv("PORT", test.env)
+
+			if got := ListenAddr(test.def); got != test.want {
+				t.Errorf("ListenAddr(%q) = %q, want %q", test.def, got, test.want)
+			}
+		})
+	}
+}
+
+type greeting struct {
+	app.Compo
+	Name string
+}
+
+func (g *greeting) Render() app.UI {
+	return app.H1().Text("Hello " + g.Name)
+}
+
+func TestRegexpRoute(t *testing.T) {
+	h := NewHandler(Config{
+		Routes:  []Route{{Path: "^/greet/[a-z]+$", Compo: &greeting{Name: "Alex"}}},
+		Handler: &app.Handler{},
+	})
+	for path, want := range map[string]int{
+		"/greet/alex":  http.StatusOK,
+		"/greet/Alex/": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("%s = %d, want %d", path, w.Code, want)
+		}
+		// As with app.Route, the page mounts a new greeting, without the
+		// name of the route one.
+		if want == http.StatusOK && !strings.Contains(w.Body.String(), "<h1>Hello </h1>") {
+			t.Errorf("%s does not render the greeting:\n%s", path, w.Body.String())
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	h := NewHandler(Config{Handler: &app.Handler{}})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+	if w.Code != http.StatusOK || w.Body.String() != "ok\n" {
+		t.Errorf("health = %d %q, want 200 %q", w.Code, w.Body.String(), "ok\n")
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	h := NewHandler(Config{
+		Handler: &app.Handler{},
+		Endpoints: []Endpoint{{
+			Pattern: "/events",
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := w.(http.Flusher); !ok {
+					t.Error("the response of the endpoint cannot be flushed")
+				}
+				w.Write([]byte("data: event\n\n"))
+			}),
+		}},
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "data: event\n\n" {
+		t.Errorf("endpoint = %d %q, want 200 %q", w.Code, w.Body.String(), "data: event\n\n")
+	}
+}
+
+func TestEmbeddedWeb(t *testing.T) {
+	web := fstest.MapFS{
+		"web/app.wasm":   {Data: []byte("wasm")},
+		"web/styles.css": {Data: []byte("body {}")},
+	}
+	version, err := WasmVersion(web, WasmFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	styles, err := FileVersion(web, "web/styles.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(Config{
+		Routes:  []Route{{Path: "/embedded", Compo: &greeting{}}},
+		Handler: &app.Handler{Styles: []string{"/web/styles.css"}},
+		Web:     web,
+	})
+
+	for path, want := range map[string]string{
+		"/web/app.wasm?v=" + version:  "wasm",
+		"/web/styles.css":             "body {}",
+		"/web/styles.css?v=" + styles: "body {}",
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Errorf("%s = %d %q, want 200 %q", path, w.Code, w.Body.String(), want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/embedded", nil))
+	if want := `href="/web/styles.css?v=` + styles + `"`; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("page without %s", want)
+	}
+}
+
+func TestRunMode(t *testing.T) {
+	if got := runMode(); got != modeListen {
+		t.Errorf("default mode = %v, want listen", got)
+	}
+
+	t.Setenv(LambdaEnv, "127.0.0.1:9001")
+	if got := runMode(); got != modeLambda {
+		t.Errorf("mode with %s set = %v, want lambda", LambdaEnv, got)
+	}
+
+	*staticFlag = true
+	defer func() { *staticFlag = false }()
+	if got := runMode(); got != modeStatic {
+		t.Errorf("mode with -static = %v, want static", got)
+	}
+}
@@ -0,0 +1,87 @@
+// Package servertest prerenders the demo routes through app.Handler, the way
+// the server does before the wasm app takes over, so that tests can assert on
+// the produced HTML.
+package servertest
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+// Page describes the expected prerendering of a path.
+type Page struct {
+	// The path to request.
+	Path string
+
+	// The HTML fragments the page must contain.
+	Contains []string
+
+	// The HTML fragments the page must not contain.
+	Excludes []string
+}
+
+// Prerender returns the status code and HTML body of the page served for path
+// by h.
+func Prerender(h http.Handler, path string) (int, string) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code, w.Body.String()
+}
+
+// Run registers routes, then checks that each of pages is prerendered with a
+// 200 status code and the expected HTML.
+func Run(t *testing.T, routes []server.Route, pages []Page) {
+	t.Helper()
+
+	h := server.NewHandler(server.Config{
+		Routes:  routes,
+		Handler: &app.Handler{},
+	})
+	for _, p := range pages {
+		p := p
+		t.Run(p.Path, func(t *testing.T) {
+			status, body := Prerender(h, p.Path)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d, want %d", status, http.StatusOK)
+			}
+			for _, s := range p.Contains {
+				if !strings.Contains(body, s) {
+					t.Errorf("page does not contain %q", s)
+				}
+			}
+			for _, s := range p.Excludes {
+				if strings.Contains(body, s) {
+					t.Errorf("page contains %q", s)
+				}
+			}
+			if t.Failed() {
+				t.Logf("page:\n%s", body)
+			}
+		})
+	}
+}
+
+// Resources checks that the web resources h references, its styles, scripts,
+// image and icons, are files of web, such as the web directory a demo embeds:
+// go:embed leaves out the symbolic links.
+func Resources(t *testing.T, h *app.Handler, web fs.FS) {
+	t.Helper()
+	paths := append(append([]string{}, h.Styles...), h.Scripts...)
+	paths = append(paths, h.Image, h.Icon.Default, h.Icon.Large, h.Icon.SVG, h.Icon.AppleTouch)
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/web/") {
+			// Not set, or a remote URL.
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(p, "/"), "?")
+		if info, err := fs.Stat(web, name); err != nil || !info.Mode().IsRegular() {
+			t.Errorf("%s is not a file of the web directory: %v", p, err)
+		}
+	}
+}
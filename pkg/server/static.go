@@ -0,0 +1,196 @@
+package server
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+var (
+	staticFlag = flag.Bool("static", false, "generate a static website instead of serving the app")
+	distFlag   = flag.String("dist", "../dist", "directory the static website is generated in, under the demo name")
+	baseFlag   = flag.String("base", "", "path the static website is served from (default /<demo>)")
+)
+
+// staticFiles are the files, besides the route pages, that a static website
+// needs to run.
+var staticFiles = []string{
+	"index.html",
+	"app.js",
+	"app-worker.js",
+	"wasm_exec.js",
+	"manifest.webmanifest",
+	"app.css",
+	"web/app.wasm",
+}
+
+// demoName returns the name of the demo being run, which is the name of the
+// working directory.
+func demoName() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "demo"
+	}
+	return filepath.Base(wd)
+}
+
+// StaticDir returns the directory the static website is generated in, as set
+// by the -dist flag: <dist>/<demo>.
+func StaticDir() string {
+	return filepath.Join(*distFlag, demoName())
+}
+
+// StaticBase returns the path the static website is served from, as set by
+// the -base flag. It defaults to /<demo>, so that the -dist directory can be
+// served as is.
+func StaticBase() string {
+	if *baseFlag != "" {
+		return *baseFlag
+	}
+	return "/" + demoName()
+}
+
+// GenerateStatic writes the static website of the demo described by cfg into
+// dir: a page per route, the manifest, the service worker, and the web
+// directory of cfg.Web along with app.wasm, which must have been built
+// beforehand. The resources are referenced under base, and every route is
+// checked to have produced a page.
+func GenerateStatic(cfg Config, dir, base string) error {
+	registerRoutes(cfg.Routes)
+
+	web := webFS(cfg.Web)
+	if _, err := fs.Stat(web, WasmFile); err != nil {
+		return fmt.Errorf("%s must be built first: %w", WasmFile, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	versionAssets(cfg.Handler, web, "")
+	if base = strings.Trim(base, "/"); base != "" {
+		cfg.Handler.Resources = Assets(web, base, cfg.Handler.Version)
+	}
+	if err := app.GenerateStaticWebsite(dir, cfg.Handler); err != nil {
+		return err
+	}
+
+	// go-app writes the page of a path ending with a slash, such as /demo/, to
+	// demo.html; it has to be demo/index.html to be served for the directory.
+	for _, r := range cfg.Routes {
+		if r.Path != "/" && !r.isRegexp() && strings.HasSuffix(r.Path, "/") {
+			p := filepath.Join(dir, filepath.FromSlash(r.Path))
+			if err := copyFile(p+".html", filepath.Join(p, "index.html")); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := copyDir(web, "web", filepath.Join(dir, "web")); err != nil {
+		return err
+	}
+	return CheckStatic(dir, cfg.Routes)
+}
+
+// CheckStatic reports an error listing the pages and files missing from the
+// static website generated in dir for routes.
+func CheckStatic(dir string, routes []Route) error {
+	files := append([]string{}, staticFiles...)
+	for _, r := range routes {
+		if !r.isRegexp() {
+			files = append(files, pageFile(r.Path))
+		}
+	}
+
+	var missing []string
+	for _, f := range files {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil || info.Size() == 0 {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("static website in %s is missing: %s", dir, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// pageFile returns the file a static website serves for path.
+func pageFile(path string) string {
+	switch {
+	case path == "/":
+		return "index.html"
+	case strings.HasSuffix(path, "/"):
+		return strings.TrimPrefix(path, "/") + "index.html"
+	case filepath.Ext(path) == "":
+		return strings.TrimPrefix(path, "/") + ".html"
+	default:
+		return strings.TrimPrefix(path, "/")
+	}
+}
+
+// generateStatic generates the static website of cfg as set by the flags.
+func generateStatic(cfg Config) {
+	dir := StaticDir()
+	if err := GenerateStatic(cfg, dir, StaticBase()); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Static website generated in", dir)
+}
+
+// copyDir copies the directory src of fsys to dst. Symbolic links are copied
+// as the file or directory they point to.
+func copyDir(fsys fs.FS, src, dst string) error {
+	return fs.WalkDir(fsys, src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(name, src)))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if d.Type().IsRegular() {
+			return copyFSFile(fsys, name, target)
+		}
+		info, err := fs.Stat(fsys, name)
+		switch {
+		case err != nil:
+			return err
+		case info.IsDir():
+			return copyDir(fsys, name, target)
+		case !info.Mode().IsRegular():
+			return errors.New("not a regular file: " + name)
+		}
+		return copyFSFile(fsys, name, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	return copyFSFile(os.DirFS(filepath.Dir(src)), filepath.Base(src), dst)
+}
+
+// copyFSFile copies the file name of fsys to dst.
+func copyFSFile(fsys fs.FS, name, dst string) error {
+	in, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+func TestGenerateStatic(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if err := os.Mkdir("web", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("web/app.wasm", []byte("wasm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A shared style sheet, as the 0B2 demos link theirs.
+	if err := os.WriteFile("styles.css", []byte("body {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../styles.css", "web/styles.css"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{
+		Routes: []Route{
+			{Path: "/", Compo: &greeting{Name: "World"}},
+			{Path: "/sam", Compo: &greeting{Name: "Sam"}},
+			{Path: "/demo/", Compo: &greeting{Name: "Demo"}},
+		},
+		Handler: &app.Handler{Name: "Greeting"},
+	}
+	dir := filepath.Join(tmp, "dist", "greeting")
+	if err := GenerateStatic(cfg, dir, "/greeting"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pages mount new greetings, without the names of the routes.
+	for file, want := range map[string]string{
+		"index.html":      "<h1>Hello </h1>",
+		"sam.html":        "<h1>Hello </h1>",
+		"demo/index.html": "<h1>Hello </h1>",
+		"web/app.wasm":    "wasm",
+		"web/styles.css":  "body {}",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s does not contain %q", file, want)
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"/greeting/app.js"`) {
+		t.Error("index.html does not load app.js from the base path")
+	}
+
+	version, err := WasmVersion(os.DirFS("."), WasmFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err = os.ReadFile(filepath.Join(dir, "app.js")); err != nil {
+		t.Fatal(err)
+	}
+	if wasm := "/greeting/web/app.wasm?v=" + version; !strings.Contains(string(b), wasm) {
+		t.Errorf("app.js does not load %s", wasm)
+	}
+}
+
+func TestGenerateStaticEmbedded(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	cfg := Config{
+		Routes:  []Route{{Path: "/", Compo: &greeting{Name: "World"}}},
+		Handler: &app.Handler{Name: "Greeting"},
+		Web: fstest.MapFS{
+			"web/app.wasm":     {Data: []byte("wasm")},
+			"web/img/icon.png": {Data: []byte("png")},
+		},
+	}
+	dir := filepath.Join(tmp, "dist")
+	if err := GenerateStatic(cfg, dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{
+		"web/app.wasm":     "wasm",
+		"web/img/icon.png": "png",
+	} {
+		if b, err := os.ReadFile(filepath.Join(dir, file)); err != nil || string(b) != want {
+			t.Errorf("%s = %q, %v, want %q", file, b, err, want)
+		}
+	}
+}
+
+func TestCheckStatic(t *testing.T) {
+	dir := t.TempDir()
+	err := CheckStatic(dir, []Route{{Path: "/missing"}, {Path: "^/pattern/.+$"}})
+	if err == nil || !strings.Contains(err.Error(), "missing.html") {
+		t.Errorf("CheckStatic() = %v, want missing.html reported", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "pattern") {
+		t.Errorf("CheckStatic() = %v, want no page for the regexp route", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Package sse streams server-sent events from a demo server to its go-app
+// app. Broker is the HTTP handler publishing the events, and Client, in the
+// app, subscribes to them through the EventSource of the browser,
+// reconnecting with an exponential backoff when the connection is lost.
+//
+// Every event gets an ID, so a client reconnecting only receives the events
+// it missed, from the last ones the broker keeps.
+package sse
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LastEventIDParam is the query parameter a client reconnecting sets to the
+// ID of the last event it received, since browsers only send the
+// Last-Event-ID header when reconnecting an EventSource by themselves.
+const LastEventIDParam = "lastEventId"
+
+// KeepAlive is how often a comment is sent on idle connections, so that
+// proxies do not close them.
+const KeepAlive = 30 * time.Second
+
+// Event is a server-sent event.
+type Event struct {
+	// The ID of the event, set by Broker.Publish.
+	ID string
+
+	// The name of the event; "message" when empty.
+	Name string
+
+	Data string
+}
+
+// Broker publishes events to the clients subscribed through its ServeHTTP.
+// Its zero value is not usable: use NewBroker.
+type Broker struct {
+	history int
+
+	mu      sync.Mutex
+	clients map[chan Event]struct{}
+	events  []Event
+	lastID  int
+}
+
+// NewBroker returns a broker keeping the last history events, which it
+// replays to the clients that subscribe: all of them to the new clients, and
+// those they missed to the clients reconnecting.
+func NewBroker(history int) *Broker {
+	return &Broker{history: history, clients: make(map[chan Event]struct{})}
+}
+
+// Publish sends an event named name with data to the subscribed clients, and
+// returns it.
+func (b *Broker) Publish(name, data string) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e := Event{ID: strconv.Itoa(b.lastID), Name: name, Data: data}
+	if b.history > 0 {
+		b.events = append(b.events, e)
+		if len(b.events) > b.history {
+			b.events = b.events[len(b.events)-b.history:]
+		}
+	}
+	for c := range b.clients {
+		select {
+		case c <- e:
+		default:
+			// The client is not keeping up; it gets the event back when it
+			// reconnects, as long as the broker keeps it.
+		}
+	}
+	return e
+}
+
+// subscribe registers a client whose last event is lastID, and returns its
+// channel along with the events it missed.
+func (b *Broker) subscribe(lastID string) (chan Event, []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := make(chan Event, 16)
+	b.clients[c] = struct{}{}
+
+	last, err := strconv.Atoi(lastID)
+	if err != nil || last > b.lastID {
+		// A new client, or one of a previous run of the server.
+		last = 0
+	}
+	var missed []Event
+	for _, e := range b.events {
+		if id, _ := strconv.Atoi(e.ID); id > last {
+			missed = append(missed, e)
+		}
+	}
+	return c, missed
+}
+
+func (b *Broker) unsubscribe(c chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, c)
+}
+
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	if id := r.URL.Query().Get(LastEventIDParam); id != "" {
+		lastID = id
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	c, missed := b.subscribe(lastID)
+	defer b.unsubscribe(c)
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	f.Flush()
+
+	keepAlive := time.NewTicker(KeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-c:
+			writeEvent(w, e)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		f.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e Event) {
+	fmt.Fprintf(w, "id: %s\n", e.ID)
+	if e.Name != "" {
+		fmt.Fprintf(w, "event: %s\n", e.Name)
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,105 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBroker(t *testing.T) {
+	b := NewBroker(2)
+	b.Publish("snippet", "one")
+	b.Publish("snippet", "two\nlines")
+	b.Publish("", "three")
+
+	s := httptest.NewServer(b)
+	defer s.Close()
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		want   []string
+	}{
+		{
+			name: "new client",
+			want: []string{"id: 2\nevent: snippet\ndata: two\ndata: lines\n\n", "id: 3\ndata: three\n\n"},
+		},
+		{
+			name:   "Last-Event-ID",
+			header: "2",
+			want:   []string{"id: 3\ndata: three\n\n"},
+		},
+		{
+			name:  "lastEventId parameter",
+			query: "?" + LastEventIDParam + "=2",
+			want:  []string{"id: 3\ndata: three\n\n"},
+		},
+		{
+			name:   "previous server run",
+			header: "42",
+			want:   []string{"id: 2\nevent: snippet\ndata: two\ndata: lines\n\n", "id: 3\ndata: three\n\n"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, closeBody := subscribe(t, s.URL+test.query, test.header)
+			defer closeBody()
+			for _, want := range test.want {
+				if got := readEvent(t, r); got != want {
+					t.Errorf("replayed event = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+
+	r, closeBody := subscribe(t, s.URL, "3")
+	defer closeBody()
+	b.Publish("snippet", "four")
+	if got, want := readEvent(t, r), "id: 4\nevent: snippet\ndata: four\n\n"; got != want {
+		t.Errorf("published event = %q, want %q", got, want)
+	}
+}
+
+func subscribe(t *testing.T, url, lastID string) (*bufio.Reader, func()) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return bufio.NewReader(res.Body), func() {
+		cancel()
+		res.Body.Close()
+	}
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		b.WriteString(line)
+		if line == "\n" || err == io.EOF {
+			return b.String()
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// ErrUnsupported is returned by Client.Run where there is no EventSource, as
+// on the server.
+var ErrUnsupported = errors.New("sse: EventSource not supported")
+
+// Status is the state of the connection of a Client.
+type Status int
+
+const (
+	// Connecting means that the client is connecting to the broker.
+	Connecting Status = iota
+
+	// Open means that the client is receiving the events.
+	Open
+
+	// Reconnecting means that the connection was lost, and that the client
+	// waits before connecting again.
+	Reconnecting
+
+	// Closed means that the client stopped.
+	Closed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Open:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	default:
+		return "closed"
+	}
+}
+
+// Client subscribes to the events of a Broker from the web browser.
+type Client struct {
+	// The URL of the broker.
+	URL string
+
+	// The names of the events to receive, on top of the unnamed ones.
+	Events []string
+
+	// Called with every event received.
+	OnEvent func(Event)
+
+	// Called whenever the status of the connection changes. retry is the
+	// time before reconnecting, when the status is Reconnecting.
+	OnStatus func(s Status, retry time.Duration)
+
+	// The time waited before reconnecting the first time after the
+	// connection was lost, doubled on every failed attempt up to MaxBackoff.
+	// Default to 1 and 30 seconds respectively.
+	MinBackoff, MaxBackoff time.Duration
+}
+
+// Run connects to the broker and calls the callbacks of the client until ctx
+// is done. It blocks, so it is meant to be called in ctx.Async, the callbacks
+// dispatching their changes to the UI goroutine with ctx.Dispatch:
+//
+//	ctx.Async(func() {
+//		c.Run(ctx)
+//	})
+func (c *Client) Run(ctx context.Context) error {
+	if app.IsServer || !app.Window().Get("EventSource").Truthy() {
+		return ErrUnsupported
+	}
+	defer c.status(Closed, 0)
+
+	var lastID string
+	for attempt := 0; ; attempt++ {
+		c.status(Connecting, 0)
+		if c.connect(ctx, &lastID) {
+			attempt = 0
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		retry := backoff(attempt, c.MinBackoff, c.MaxBackoff)
+		c.status(Reconnecting, retry)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+	}
+}
+
+// connect receives the events until the connection is lost or ctx is done,
+// updating lastID, and reports whether the connection opened.
+func (c *Client) connect(ctx context.Context, lastID *string) (opened bool) {
+	done := make(chan struct{})
+	events := make(chan Event)
+	opens := make(chan struct{}, 1)
+	errs := make(chan struct{}, 1)
+
+	// Deferred in reverse order: the pending callbacks return, the event
+	// source is closed, then the callbacks released.
+	onEvent := app.FuncOf(func(this app.Value, args []app.Value) any {
+		e := args[0]
+		select {
+		case events <- Event{ID: e.Get("lastEventId").String(), Name: e.Get("type").String(), Data: e.Get("data").String()}:
+		case <-done:
+		}
+		return nil
+	})
+	defer onEvent.Release()
+	onOpen := app.FuncOf(func(this app.Value, args []app.Value) any {
+		notify(opens)
+		return nil
+	})
+	defer onOpen.Release()
+	onError := app.FuncOf(func(this app.Value, args []app.Value) any {
+		notify(errs)
+		return nil
+	})
+	defer onError.Release()
+	es := app.Window().Get("EventSource").New(c.url(*lastID))
+	defer es.Call("close")
+	defer close(done)
+
+	es.Call("addEventListener", "open", onOpen)
+	es.Call("addEventListener", "error", onError)
+	es.Call("addEventListener", "message", onEvent)
+	for _, name := range c.Events {
+		es.Call("addEventListener", name, onEvent)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return opened
+		case <-errs:
+			// The browser would reconnect at its own pace: the client does
+			// with its backoff instead.
+			return opened
+		case <-opens:
+			opened = true
+			c.status(Open, 0)
+		case e := <-events:
+			if e.ID != "" {
+				*lastID = e.ID
+			}
+			if c.OnEvent != nil {
+				c.OnEvent(e)
+			}
+		}
+	}
+}
+
+// url returns the URL of the broker, with the ID of the last event received.
+func (c *Client) url(lastID string) string {
+	if lastID == "" {
+		return c.URL
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return c.URL
+	}
+	q := u.Query()
+	q.Set(LastEventIDParam, lastID)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (c *Client) status(s Status, retry time.Duration) {
+	if c.OnStatus != nil {
+		c.OnStatus(s, retry)
+	}
+}
+
+// backoff returns the time to wait before the reconnection attempt, starting
+// at 0: min doubled attempt times, up to max.
+func backoff(attempt int, min, max time.Duration) time.Duration {
+	if min <= 0 {
+		min = time.Second
+	}
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
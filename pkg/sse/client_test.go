@@ -0,0 +1,41 @@
+package sse
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+		want     time.Duration
+	}{
+		{0, 0, 0, time.Second},
+		{1, 0, 0, 2 * time.Second},
+		{4, 0, 0, 16 * time.Second},
+		{5, 0, 0, 30 * time.Second},
+		{100, 0, 0, 30 * time.Second},
+		{2, 100 * time.Millisecond, time.Second, 400 * time.Millisecond},
+		{4, 100 * time.Millisecond, time.Second, time.Second},
+	}
+	for _, test := range tests {
+		if got := backoff(test.attempt, test.min, test.max); got != test.want {
+			t.Errorf("backoff(%d, %v, %v) = %v, want %v", test.attempt, test.min, test.max, got, test.want)
+		}
+	}
+}
+
+func TestClientURL(t *testing.T) {
+	c := &Client{URL: "/snippets?lang=js"}
+	if got := c.url(""); got != c.URL {
+		t.Errorf("url without last event = %q, want %q", got, c.URL)
+	}
+	if got, want := c.url("7"), "/snippets?lang=js&lastEventId=7"; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+	if err := c.Run(context.Background()); err != ErrUnsupported {
+		t.Errorf("Run on the server: error = %v, want %v", err, ErrUnsupported)
+	}
+}
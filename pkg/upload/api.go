@@ -0,0 +1,108 @@
+//go:build !wasm
+
+package upload
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/suntong/go-app-demos/pkg/jsonapi"
+)
+
+// Handler serves the API of s under APIPath:
+//
+//	GET    APIPath                      answers the uploads as JSON, the latest first
+//	POST   APIPath                      starts the session of a JSON Request, answered as JSON
+//	PUT    APIPath+"sessions/"+id?offset=n  writes the chunk of the body at offset n
+//	DELETE APIPath+id                   deletes the upload id
+//
+// The chunks are answered with the session, whose Upload is set once the
+// image is complete. The sessions are answered with 503 Service Unavailable
+// while MaxSessions are open.
+func Handler(s *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := strings.TrimPrefix(r.URL.Path, APIPath)
+		sid, chunk := strings.CutPrefix(p, "sessions/")
+		switch {
+		case p == "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+			w.Header().Set("Cache-Control", "no-store")
+			jsonapi.WriteJSON(w, http.StatusOK, s.List())
+		case p == "" && r.Method == http.MethodPost:
+			begin(s, w, r)
+		case p == "":
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			jsonapi.Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		case chunk && r.Method == http.MethodPut:
+			write(s, w, r, sid)
+		case chunk:
+			w.Header().Set("Allow", http.MethodPut)
+			jsonapi.Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		case r.Method != http.MethodDelete:
+			w.Header().Set("Allow", http.MethodDelete)
+			jsonapi.Error(w, http.StatusMethodNotAllowed, "method not allowed")
+		case !ValidID(p):
+			api.WriteError(w, ErrNotFound)
+		default:
+			if err := s.Delete(p); err != nil {
+				api.WriteError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// Files serves the images of s, and their thumbnails, under FilesPath. Their
+// names change with their content, so they are cached for good.
+func Files(s *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := s.path(strings.TrimPrefix(r.URL.Path, FilesPath))
+		if path == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		http.ServeFile(w, r, path)
+	})
+}
+
+func begin(s *Store, w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		api.WriteError(w, invalid(err.Error()))
+		return
+	}
+	ss, err := s.Begin(req)
+	if err != nil {
+		api.WriteError(w, err)
+		return
+	}
+	jsonapi.WriteJSON(w, http.StatusCreated, ss)
+}
+
+func write(s *Store, w http.ResponseWriter, r *http.Request, id string) {
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		api.WriteError(w, invalid("invalid offset"))
+		return
+	}
+	chunk, err := io.ReadAll(http.MaxBytesReader(w, r.Body, ChunkSize))
+	if err != nil {
+		api.WriteError(w, invalid("chunk larger than 256 KiB"))
+		return
+	}
+	ss, err := s.Write(id, offset, chunk)
+	if err != nil {
+		api.WriteError(w, err)
+		return
+	}
+	if ss.Upload != nil {
+		w.Header().Set("Location", ss.Upload.URL())
+		jsonapi.WriteJSON(w, http.StatusCreated, ss)
+		return
+	}
+	jsonapi.WriteJSON(w, http.StatusOK, ss)
+}
@@ -0,0 +1,112 @@
+//go:build !wasm
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suntong/go-app-demos/pkg/jsonapi/jsonapitest"
+)
+
+func TestAPI(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	mux := http.NewServeMux()
+	mux.Handle(APIPath, Handler(s))
+	mux.Handle(FilesPath, Files(s))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := Client{URL: srv.URL}
+	ctx := context.Background()
+
+	data := pngData(t, 300, 300, 4)
+	var sent []int
+	u, err := c.Upload(ctx, "image/png", data, func(n, total int) {
+		if total != len(data) {
+			t.Errorf("progress total = %d, want %d", total, len(data))
+		}
+		sent = append(sent, n)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sent); n != (len(data)+ChunkSize-1)/ChunkSize || sent[n-1] != len(data) {
+		t.Errorf("progress = %v, want one call per chunk", sent)
+	}
+	if list, err := c.List(ctx); err != nil || len(list) != 1 || list[0].ID != u.ID {
+		t.Errorf("list = %+v, %v, want the upload", list, err)
+	}
+
+	for _, p := range []string{u.URL(), u.ThumbURL()} {
+		res, err := http.Get(srv.URL + p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("GET %s = %s, %s", p, res.Status, res.Header.Get("Content-Type"))
+		}
+		if p == u.URL() && !bytes.Equal(body, data) {
+			t.Errorf("GET %s: not the image", p)
+		}
+	}
+
+	if err := c.Delete(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, u.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("delete again: error = %v, want ErrNotFound", err)
+	}
+	if res, err := http.Get(srv.URL + u.URL()); err != nil || res.StatusCode != http.StatusNotFound {
+		t.Errorf("GET deleted image = %v, %v", res.Status, err)
+	}
+
+	_, err = c.Upload(ctx, "image/png", []byte("GIF89a not a PNG"), nil)
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "image/gif content, not image/png") {
+		t.Errorf("upload invalid: error = %v, want ErrInvalid", err)
+	}
+
+	for len(s.sessions) < MaxSessions {
+		if _, err := s.Begin(Request{Type: "image/png", Size: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.Upload(ctx, "image/png", data, nil); err != ErrBusy {
+		t.Errorf("upload with too many sessions: error = %v, want ErrBusy", err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	jsonapitest.Errors(t, Handler(s), []jsonapitest.Request{
+		{Method: http.MethodDelete, Path: APIPath, Want: http.StatusMethodNotAllowed},
+		{Method: http.MethodGet, Path: APIPath + strings.Repeat("a", 64), Want: http.StatusMethodNotAllowed},
+		{Method: http.MethodDelete, Path: APIPath + "nope", Want: http.StatusNotFound},
+		{Method: http.MethodDelete, Path: APIPath + strings.Repeat("a", 64), Want: http.StatusNotFound},
+		{Method: http.MethodPost, Path: APIPath, Body: "{", Want: http.StatusBadRequest},
+		{Method: http.MethodPost, Path: APIPath, Body: `{"type": "text/plain", "size": 4}`, Want: http.StatusBadRequest},
+		{Method: http.MethodGet, Path: APIPath + "sessions/nope", Want: http.StatusMethodNotAllowed},
+		{Method: http.MethodPut, Path: APIPath + "sessions/nope?offset=0", Body: "data", Want: http.StatusNotFound},
+		{Method: http.MethodPut, Path: APIPath + "sessions/nope", Body: "data", Want: http.StatusBadRequest},
+		{Method: http.MethodPut, Path: APIPath + "sessions/nope?offset=0", Body: strings.Repeat("x", ChunkSize+1), Want: http.StatusBadRequest},
+	})
+
+	files := Files(s)
+	for _, p := range []string{FilesPath, FilesPath + "../go.mod", FilesPath + strings.Repeat("a", 64) + ".png", FilesPath + tempPrefix + "x"} {
+		w := httptest.NewRecorder()
+		files.ServeHTTP(w, httptest.NewRequest(http.MethodGet, p, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want %d", p, w.Code, http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Client calls the API of a server. In the web browser, net/http goes through
+// the fetch API, so Client works the same in the app and in tests.
+type Client struct {
+	// The URL of the server. Defaults to the one of the page, in the web
+	// browser.
+	URL string
+}
+
+// Upload uploads the image data of type typ, ChunkSize bytes at a time, and
+// returns its upload. Progress, if not nil, is called after each chunk with
+// the number of bytes sent so far.
+func (c Client) Upload(ctx context.Context, typ string, data []byte, progress func(sent, total int)) (Upload, error) {
+	r := Request{Type: typ, Size: int64(len(data))}
+	if err := r.Validate(); err != nil {
+		return Upload{}, err
+	}
+	body, err := json.Marshal(r)
+	if err != nil {
+		return Upload{}, err
+	}
+	var ss Session
+	if err := c.do(ctx, http.MethodPost, APIPath, "application/json", body, &ss); err != nil {
+		return Upload{}, err
+	}
+
+	for sent := 0; ss.Upload == nil; {
+		if sent == len(data) {
+			return Upload{}, fmt.Errorf("upload API: image sent, but not stored")
+		}
+		chunk := chunkAt(data, sent)
+		p := APIPath + "sessions/" + ss.ID + "?offset=" + strconv.Itoa(sent)
+		if err := c.do(ctx, http.MethodPut, p, "application/octet-stream", chunk, &ss); err != nil {
+			return Upload{}, err
+		}
+		sent += len(chunk)
+		if progress != nil {
+			progress(sent, len(data))
+		}
+	}
+	return *ss.Upload, nil
+}
+
+// List returns the uploads, the latest first.
+func (c Client) List(ctx context.Context) ([]Upload, error) {
+	var list []Upload
+	err := c.do(ctx, http.MethodGet, APIPath, "", nil, &list)
+	return list, err
+}
+
+// Delete deletes the upload id.
+func (c Client) Delete(ctx context.Context, id string) error {
+	if !ValidID(id) {
+		return ErrNotFound
+	}
+	return c.do(ctx, http.MethodDelete, APIPath+id, "", nil, nil)
+}
+
+// do sends a request with the body of type ctype to the API path p, which
+// may have a query, and decodes the JSON answered into v, unless v is nil.
+func (c Client) do(ctx context.Context, method, p, ctype string, body []byte, v any) error {
+	return api.Do(ctx, c.URL, method, p, ctype, body, v)
+}
@@ -0,0 +1,35 @@
+//go:build !wasm
+
+package upload
+
+import (
+	"flag"
+	"log"
+
+	"github.com/suntong/go-app-demos/pkg/server"
+)
+
+var dirFlag = flag.String("uploads", "uploads", "directory the pasted images are stored in")
+
+// Dir returns the directory the demos store the images in, as set by the
+// -uploads flag. The flags are parsed first.
+func Dir() string {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	return *dirFlag
+}
+
+// Endpoints opens the store of dir, which the Gallery page is prerendered
+// from, and returns the API serving it along with the images.
+func Endpoints(dir string) []server.Endpoint {
+	s, err := Open(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	Uploads = s
+	return []server.Endpoint{
+		{Pattern: APIPath, Handler: Handler(s)},
+		{Pattern: FilesPath, Handler: Files(s)},
+	}
+}
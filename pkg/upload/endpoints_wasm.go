@@ -0,0 +1,14 @@
+package upload
+
+import "github.com/suntong/go-app-demos/pkg/server"
+
+// Dir returns no directory in the web browser, where the store does not
+// exist.
+func Dir() string {
+	return ""
+}
+
+// Endpoints does nothing in the web browser.
+func Endpoints(dir string) []server.Endpoint {
+	return nil
+}
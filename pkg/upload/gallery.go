@@ -0,0 +1,108 @@
+package upload
+
+import (
+	"fmt"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// Uploads, set on the server-side, is where the Gallery page is prerendered
+// from: the Store of the demo, whose routes mount it at GalleryRoute.
+var Uploads interface {
+	List() []Upload
+}
+
+// Gallery lists the uploaded images, as thumbnails linking to the images,
+// each with a delete button.
+type Gallery struct {
+	app.Compo
+	uploads []Upload
+	loaded  bool
+	err     error
+}
+
+func (g *Gallery) OnPreRender(ctx app.Context) {
+	if Uploads != nil {
+		g.uploads, g.loaded = Uploads.List(), true
+	}
+}
+
+// OnNav fetches the uploads through the API, those of the prerendered page
+// being as old as the page.
+func (g *Gallery) OnNav(ctx app.Context) {
+	ctx.Async(func() {
+		list, err := Client{}.List(ctx)
+		ctx.Dispatch(func(ctx app.Context) {
+			g.uploads, g.loaded, g.err = list, err == nil, err
+		})
+	})
+}
+
+func (g *Gallery) Render() app.UI {
+	var status app.UI
+	switch {
+	case g.err != nil:
+		status = app.P().Class("error").Text(g.err.Error())
+	case !g.loaded:
+		status = app.P().Class("status").Text("Loading...")
+	case len(g.uploads) == 0:
+		status = app.P().Class("status").Text("No uploads yet.")
+	default:
+		status = app.P().Class("status").Text(count(len(g.uploads)))
+	}
+	return app.Div().Body(
+		app.H1().Text("Uploads"),
+		status,
+		app.Div().Class("uploads").Style("display", "flex").Style("flex-wrap", "wrap").Body(
+			app.Range(g.uploads).Slice(func(i int) app.UI {
+				u := g.uploads[i]
+				return app.Figure().Style("margin", "4px").Body(
+					app.A().Href(u.URL()).Target("_blank").Body(
+						app.Img().Src(u.ThumbURL()).Alt(u.Name()),
+					),
+					app.FigCaption().Body(
+						app.Text(fmt.Sprintf("%dx%d, %s ", u.Width, u.Height, size(u.Size))),
+						app.Button().Text("Delete").OnClick(g.onDelete(u.ID)),
+					),
+				)
+			}),
+		),
+		app.A().Href("./").Text("Paste more images"),
+	)
+}
+
+// onDelete returns the handler of the delete button of the upload id.
+func (g *Gallery) onDelete(id string) app.EventHandler {
+	return func(ctx app.Context, e app.Event) {
+		ctx.Async(func() {
+			err := Client{}.Delete(ctx, id)
+			ctx.Dispatch(func(ctx app.Context) {
+				if g.err = err; err != nil {
+					return
+				}
+				for i, u := range g.uploads {
+					if u.ID == id {
+						g.uploads = append(g.uploads[:i:i], g.uploads[i+1:]...)
+						break
+					}
+				}
+			})
+		})
+	}
+}
+
+// count returns the number n of uploads.
+func count(n int) string {
+	if n == 1 {
+		return "1 upload"
+	}
+	return fmt.Sprintf("%d uploads", n)
+}
+
+// size returns n bytes in KiB, or MiB from 1 MiB.
+func size(n int64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+}
@@ -0,0 +1,50 @@
+package upload
+
+import (
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+// Progress is the upload of an image by the app, as the page shows it.
+type Progress struct {
+	// The bytes of the image sent so far, out of Total, which is zero until
+	// the first chunk is sent.
+	Sent, Total int
+
+	// The upload of the image, once it is stored.
+	Upload *Upload
+
+	// The error uploading the image.
+	Err error
+}
+
+// Send uploads the image data of type typ, updating p as its chunks are
+// sent. It blocks until the image is stored, so it is called from
+// ctx.Async, each image with its own Progress.
+func (p *Progress) Send(ctx app.Context, typ string, data []byte) {
+	u, err := Client{}.Upload(ctx, typ, data, func(sent, total int) {
+		ctx.Dispatch(func(ctx app.Context) {
+			p.Sent, p.Total = sent, total
+		})
+	})
+	ctx.Dispatch(func(ctx app.Context) {
+		if p.Err = err; err == nil {
+			p.Upload = &u
+		}
+	})
+}
+
+// UI renders the state of p: the progress of the upload, its error, or a
+// link to the stored image.
+func (p *Progress) UI() app.UI {
+	switch {
+	case p.Upload != nil:
+		return app.A().Href(p.Upload.URL()).Target("_blank").Text("Saved")
+	case p.Err != nil:
+		return app.Span().Class("error").Text("Not saved: " + p.Err.Error())
+	case p.Total == 0:
+		// Indeterminate, until the first chunk is sent.
+		return app.Progress()
+	default:
+		return app.Progress().Max(p.Total).Value(p.Sent)
+	}
+}
@@ -0,0 +1,28 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxence-charriere/go-app/v9/pkg/app"
+)
+
+func TestProgressUI(t *testing.T) {
+	id := strings.Repeat("a", 64)
+	for _, test := range []struct {
+		p    Progress
+		want []string
+	}{
+		{Progress{}, []string{"<progress>"}},
+		{Progress{Sent: 1, Total: 4}, []string{"<progress", `max="4"`, `value="1"`}},
+		{Progress{Err: ErrBusy}, []string{`class="error"`, "Not saved: " + ErrBusy.Error()}},
+		{Progress{Upload: &Upload{ID: id, Type: "image/png"}}, []string{`href="/uploads/` + id + `.png"`, ">Saved</a>"}},
+	} {
+		html := app.HTMLString(test.p.UI())
+		for _, s := range test.want {
+			if !strings.Contains(html, s) {
+				t.Errorf("%+v: UI() = %s, want %s", test.p, html, s)
+			}
+		}
+	}
+}
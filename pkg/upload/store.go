@@ -0,0 +1,374 @@
+//go:build !wasm
+
+package upload
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SessionTimeout is how long an upload session lasts without receiving
+	// a chunk.
+	SessionTimeout = 10 * time.Minute
+
+	// MaxSessions is the maximum number of upload sessions open at once,
+	// each of which has a file open.
+	MaxSessions = 64
+)
+
+// tempPrefix starts the names of the files of the upload sessions.
+const tempPrefix = ".upload-"
+
+var namePattern = regexp.MustCompile(`^([0-9a-f]{64})(\.png|\.jpg|\.gif)$`)
+
+// Store keeps the uploaded images, and their thumbnails, in a directory.
+type Store struct {
+	dir string
+	now func() time.Time
+
+	mu       sync.Mutex
+	uploads  map[string]Upload
+	sessions map[string]*session
+}
+
+// session is an upload session: the chunks received so far are written to
+// file. While busy, a chunk is being written, or the complete image stored,
+// without the lock of the store: the other chunks are refused, and the
+// session does not time out.
+type session struct {
+	Session
+	file    *os.File
+	hash    hash.Hash
+	touched time.Time
+	busy    bool
+}
+
+// Open returns the store of the directory dir, creating it if needed. The
+// files of the sessions a previous server left are removed, and the missing
+// thumbnails made.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		dir:      dir,
+		now:      time.Now,
+		uploads:  make(map[string]Upload),
+		sessions: make(map[string]*session),
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), tempPrefix) {
+			os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		m := namePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		u, err := s.load(m[1], e.Name())
+		if err != nil {
+			return nil, err
+		}
+		s.uploads[u.ID] = u
+	}
+	return s, nil
+}
+
+// load returns the upload of the image file name, whose ID is id.
+func (s *Store) load(id, name string) (Upload, error) {
+	path := filepath.Join(s.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Upload{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Upload{}, err
+	}
+	u := Upload{ID: id, Type: http.DetectContentType(data), Size: info.Size(), Created: info.ModTime()}
+	img, err := decode(u.Type, data)
+	if err != nil {
+		return Upload{}, fmt.Errorf("%s: %w", path, err)
+	}
+	u.Width, u.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	if _, err := os.Stat(filepath.Join(s.dir, id+thumbSuffix)); os.IsNotExist(err) {
+		err = writeThumb(filepath.Join(s.dir, id+thumbSuffix), img)
+	}
+	return u, err
+}
+
+// List returns the uploads, the latest first.
+func (s *Store) List() []Upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Upload, 0, len(s.uploads))
+	for _, u := range s.uploads {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Created.Equal(list[j].Created) {
+			return list[i].Created.After(list[j].Created)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Get returns the upload id.
+func (s *Store) Get(id string) (Upload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return Upload{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// Delete removes the upload id, and its thumbnail.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.uploads, id)
+	if err := os.Remove(filepath.Join(s.dir, u.Name())); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(s.dir, u.ID+thumbSuffix))
+}
+
+// Begin starts the upload session of the image of r. The sessions that timed
+// out are dropped first; ErrBusy is returned if MaxSessions are still open.
+func (s *Store) Begin(r Request) (Session, error) {
+	if err := r.Validate(); err != nil {
+		return Session{}, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Session{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, ss := range s.sessions {
+		if !ss.busy && now.Sub(ss.touched) > SessionTimeout {
+			s.drop(ss)
+		}
+	}
+	if len(s.sessions) >= MaxSessions {
+		return Session{}, ErrBusy
+	}
+	f, err := os.CreateTemp(s.dir, tempPrefix+"*")
+	if err != nil {
+		return Session{}, err
+	}
+	ss := &session{
+		Session: Session{ID: hex.EncodeToString(id), Type: r.Type, Size: r.Size},
+		file:    f,
+		hash:    sha256.New(),
+		touched: now,
+	}
+	s.sessions[ss.ID] = ss
+	return ss.Session, nil
+}
+
+// Write writes the chunk of the session id at offset, which must be the
+// number of bytes the session received so far. Once the image is complete,
+// it is checked, stored, and returned in the Upload of the session; the same
+// image uploaded before is returned as it was.
+//
+// The chunk is written, and the image decoded, without holding the lock of
+// the store, so that the sessions do not wait for one another.
+func (s *Store) Write(id string, offset int64, chunk []byte) (Session, error) {
+	ss, err := s.claim(id, offset, chunk)
+	if err != nil {
+		return Session{}, err
+	}
+	if _, err := ss.file.Write(chunk); err != nil {
+		s.end(ss)
+		return Session{}, err
+	}
+	ss.hash.Write(chunk)
+	if received, complete := s.received(ss, len(chunk)); !complete {
+		return received, nil
+	}
+
+	u, err := s.complete(ss)
+	s.end(ss)
+	if err != nil {
+		return Session{}, err
+	}
+	ss.Upload = &u
+	return ss.Session, nil
+}
+
+// claim returns the session id, marked busy, to write chunk at offset.
+func (s *Store) claim(id string, offset int64, chunk []byte) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss, ok := s.sessions[id]
+	switch {
+	case !ok:
+		return nil, ErrNotFound
+	case ss.busy:
+		return nil, invalid("chunk sent before the previous one was written")
+	case offset != ss.Received:
+		return nil, invalid(fmt.Sprintf("chunk at offset %d, want %d", offset, ss.Received))
+	case len(chunk) == 0 || len(chunk) > ChunkSize:
+		return nil, invalid(fmt.Sprintf("chunk of %d bytes", len(chunk)))
+	case ss.Received+int64(len(chunk)) > ss.Size:
+		return nil, invalid(fmt.Sprintf("more than the %d bytes of the image", ss.Size))
+	}
+	ss.busy = true
+	return ss, nil
+}
+
+// received counts the n bytes written to the busy session ss, and returns
+// the session, along with whether its image is complete. Until then, the
+// session is no longer busy.
+func (s *Store) received(ss *session, n int) (Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss.Received += int64(n)
+	ss.touched = s.now()
+	ss.busy = ss.Received == ss.Size
+	return ss.Session, ss.busy
+}
+
+// complete checks the image of the complete session ss, and stores it along
+// with its thumbnail. Only the files are renamed with the lock of the store
+// held: the image is decoded, and its thumbnail written, before.
+func (s *Store) complete(ss *session) (Upload, error) {
+	id := hex.EncodeToString(ss.hash.Sum(nil))
+	if u, err := s.Get(id); err == nil {
+		return u, nil
+	}
+	if _, err := ss.file.Seek(0, io.SeekStart); err != nil {
+		return Upload{}, err
+	}
+	data, err := io.ReadAll(ss.file)
+	if err != nil {
+		return Upload{}, err
+	}
+	if typ := http.DetectContentType(data); typ != ss.Type {
+		return Upload{}, invalid(fmt.Sprintf("%s content, not %s", typ, ss.Type))
+	}
+	img, err := decode(ss.Type, data)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	u := Upload{
+		ID:      id,
+		Type:    ss.Type,
+		Size:    ss.Size,
+		Width:   img.Bounds().Dx(),
+		Height:  img.Bounds().Dy(),
+		Created: s.now(),
+	}
+	thumb := ss.file.Name() + thumbSuffix
+	if err := writeThumb(thumb, img); err != nil {
+		return Upload{}, err
+	}
+	ss.file.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another session may have stored the same image meanwhile.
+	if prev, ok := s.uploads[id]; ok {
+		return prev, nil
+	}
+	if err := os.Rename(thumb, filepath.Join(s.dir, id+thumbSuffix)); err != nil {
+		return Upload{}, err
+	}
+	if err := os.Rename(ss.file.Name(), filepath.Join(s.dir, u.Name())); err != nil {
+		return Upload{}, err
+	}
+	s.uploads[id] = u
+	return u, nil
+}
+
+// end drops the session ss, done with.
+func (s *Store) end(ss *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(ss)
+}
+
+// drop ends the session ss, removing its files unless they were stored.
+func (s *Store) drop(ss *session) {
+	ss.file.Close()
+	os.Remove(ss.file.Name())
+	os.Remove(ss.file.Name() + thumbSuffix)
+	delete(s.sessions, ss.ID)
+}
+
+// writeThumb writes the thumbnail of img to the file path.
+func writeThumb(path string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Thumbnail(img, ThumbSize)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// path returns the path of the file name of the store: an image or a
+// thumbnail, or "" for any other name.
+func (s *Store) path(name string) string {
+	id, thumb := strings.CutSuffix(name, thumbSuffix)
+	if m := namePattern.FindStringSubmatch(name); m != nil {
+		id = m[1]
+	} else if !thumb {
+		return ""
+	}
+	u, err := s.Get(id)
+	if err != nil || (!thumb && name != u.Name()) {
+		return ""
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// decode decodes the image data of type typ, checking its number of pixels
+// first.
+func decode(typ string, data []byte) (image.Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	switch {
+	case err != nil:
+		return nil, invalid(err.Error())
+	case "image/"+format != typ:
+		return nil, invalid(format + " image, not " + typ)
+	case cfg.Width*cfg.Height > MaxPixels:
+		return nil, invalid(fmt.Sprintf("image of %dx%d pixels, more than 25 megapixels", cfg.Width, cfg.Height))
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	return img, nil
+}
@@ -0,0 +1,276 @@
+//go:build !wasm
+
+package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pngData returns a w by h PNG image of random pixels, which PNG hardly
+// compresses, drawn from seed.
+func pngData(t *testing.T, w, h int, seed int64) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	rand.New(rand.NewSource(seed)).Read(img.Pix)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func openStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+// put uploads data through s, in chunks of ChunkSize bytes.
+func put(s *Store, typ string, data []byte) (Upload, error) {
+	ss, err := s.Begin(Request{Type: typ, Size: int64(len(data))})
+	if err != nil {
+		return Upload{}, err
+	}
+	for off := 0; off < len(data); off += ChunkSize {
+		ss, err = s.Write(ss.ID, int64(off), chunkAt(data, off))
+		if err != nil {
+			return Upload{}, err
+		}
+	}
+	if ss.Upload == nil {
+		return Upload{}, errors.New("no upload")
+	}
+	return *ss.Upload, nil
+}
+
+func TestStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := openStore(t, &now)
+
+	// Large enough for a few chunks.
+	data := pngData(t, 400, 400, 1)
+	if len(data) <= 2*ChunkSize {
+		t.Fatalf("image of %d bytes, want more than two chunks", len(data))
+	}
+	u, err := put(s, "image/png", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	want := Upload{ID: hex.EncodeToString(sum[:]), Type: "image/png", Size: int64(len(data)), Width: 400, Height: 400, Created: now}
+	if u != want {
+		t.Errorf("upload = %+v, want %+v", u, want)
+	}
+	stored, err := os.ReadFile(filepath.Join(s.dir, u.Name()))
+	if err != nil || !bytes.Equal(stored, data) {
+		t.Errorf("stored image: %v", err)
+	}
+	thumb, err := os.Open(filepath.Join(s.dir, u.ID+thumbSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := png.DecodeConfig(thumb)
+	thumb.Close()
+	if err != nil || cfg.Width != ThumbSize || cfg.Height != ThumbSize {
+		t.Errorf("thumbnail of %dx%d, %v", cfg.Width, cfg.Height, err)
+	}
+
+	// The same image is stored once.
+	now = now.Add(time.Minute)
+	if again, err := put(s, "image/png", data); err != nil || again != u {
+		t.Errorf("upload again = %+v, %v, want %+v", again, err, u)
+	}
+	other, err := put(s, "image/png", pngData(t, 2, 3, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list := s.List(); len(list) != 2 || list[0] != other || list[1] != u {
+		t.Errorf("list = %+v, want the two uploads, the latest first", list)
+	}
+
+	// Opened again, the store has the same uploads, but for their times, and
+	// makes the missing thumbnails.
+	os.Remove(filepath.Join(s.dir, u.ID+thumbSuffix))
+	os.WriteFile(filepath.Join(s.dir, tempPrefix+"left"), data[:10], 0o644)
+	s2, err := Open(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s2.Get(u.ID); err != nil || got.Width != 400 || got.Type != "image/png" {
+		t.Errorf("get after open = %+v, %v", got, err)
+	}
+	if s2.path(u.ID+thumbSuffix) == "" {
+		t.Error("thumbnail not made again")
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, tempPrefix+"left")); !os.IsNotExist(err) {
+		t.Errorf("session file left: %v", err)
+	}
+
+	if err := s.Delete(u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(u.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get deleted: error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(u.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("delete again: error = %v, want ErrNotFound", err)
+	}
+	if s.path(u.Name()) != "" || s.path(u.ID+thumbSuffix) != "" {
+		t.Error("deleted files still served")
+	}
+}
+
+func TestStoreInvalid(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	data := pngData(t, 4, 4, 3)
+
+	for _, r := range []Request{
+		{Type: "image/webp", Size: 10},
+		{Type: "image/png", Size: 0},
+		{Type: "image/png", Size: MaxSize + 1},
+	} {
+		if _, err := s.Begin(r); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Begin(%+v): error = %v, want ErrInvalid", r, err)
+		}
+	}
+
+	// Not a JPEG image.
+	if _, err := put(s, "image/jpeg", data); !errors.Is(err, ErrInvalid) {
+		t.Errorf("PNG image as JPEG: error = %v, want ErrInvalid", err)
+	}
+	// Not an image.
+	if _, err := put(s, "image/png", []byte("\x89PNG\r\n\x1a\nnot quite")); !errors.Is(err, ErrInvalid) {
+		t.Errorf("broken image: error = %v, want ErrInvalid", err)
+	}
+
+	ss, err := s.Begin(Request{Type: "image/png", Size: int64(len(data))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write(ss.ID, 1, data); !errors.Is(err, ErrInvalid) {
+		t.Errorf("write at the wrong offset: error = %v, want ErrInvalid", err)
+	}
+	if _, err := s.Write(ss.ID, 0, append(data, 0)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("write too much: error = %v, want ErrInvalid", err)
+	}
+	if _, err := s.Write("nope", 0, data); !errors.Is(err, ErrNotFound) {
+		t.Errorf("write to no session: error = %v, want ErrNotFound", err)
+	}
+
+	// The session times out once another one begins.
+	now = now.Add(SessionTimeout + time.Second)
+	if _, err := s.Begin(Request{Type: "image/png", Size: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Write(ss.ID, 0, data); !errors.Is(err, ErrNotFound) {
+		t.Errorf("write to a timed out session: error = %v, want ErrNotFound", err)
+	}
+	if len(s.List()) != 0 {
+		t.Errorf("list = %+v, want none", s.List())
+	}
+}
+
+func TestStoreSessions(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	data := pngData(t, 4, 4, 4)
+	r := Request{Type: "image/png", Size: int64(len(data))}
+
+	first, err := s.Begin(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < MaxSessions; i++ {
+		if _, err := s.Begin(r); err != nil {
+			t.Fatalf("session %d: %v", i, err)
+		}
+	}
+	if _, err := s.Begin(r); !errors.Is(err, ErrBusy) {
+		t.Errorf("Begin() with %d sessions: error = %v, want ErrBusy", MaxSessions, err)
+	}
+
+	// A busy session refuses the other chunks, and does not time out.
+	s.sessions[first.ID].busy = true
+	if _, err := s.Write(first.ID, 0, data); !errors.Is(err, ErrInvalid) {
+		t.Errorf("write to a busy session: error = %v, want ErrInvalid", err)
+	}
+	now = now.Add(SessionTimeout + time.Second)
+	if _, err := s.Begin(r); err != nil {
+		t.Fatalf("Begin() once the sessions timed out: %v", err)
+	}
+	if len(s.sessions) != 2 || s.sessions[first.ID] == nil {
+		t.Errorf("%d sessions left, want the busy one and the new one", len(s.sessions))
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	now := time.Now()
+	s := openStore(t, &now)
+	same := pngData(t, 300, 300, 5)
+
+	// The same image uploaded by several sessions at once, along with
+	// other images, is stored once.
+	const n = 8
+	images := make([][]byte, 2*n)
+	for i := range images {
+		images[i] = same
+		if i%2 == 1 {
+			images[i] = pngData(t, 40, 40, int64(100+i))
+		}
+	}
+	uploads := make([]Upload, len(images))
+	errs := make([]error, len(images))
+	var wg sync.WaitGroup
+	for i, data := range images {
+		wg.Add(1)
+		go func(i int, data []byte) {
+			defer wg.Done()
+			uploads[i], errs[i] = put(s, "image/png", data)
+		}(i, data)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+		if i%2 == 0 && uploads[i] != uploads[0] {
+			t.Errorf("upload %d = %+v, want %+v", i, uploads[i], uploads[0])
+		}
+	}
+	if list := s.List(); len(list) != n+1 {
+		t.Errorf("%d uploads, want %d", len(list), n+1)
+	}
+	if len(s.sessions) != 0 {
+		t.Errorf("%d sessions left", len(s.sessions))
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), tempPrefix) {
+			t.Errorf("session file %s left", e.Name())
+		}
+	}
+	if len(entries) != 2*(n+1) {
+		t.Errorf("%d files, want %d images and their thumbnails", len(entries), n+1)
+	}
+}
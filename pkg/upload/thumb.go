@@ -0,0 +1,61 @@
+package upload
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Thumbnail returns img scaled down to fit in a size by size square, keeping
+// its aspect ratio. Each pixel of the thumbnail is the average of the block
+// of pixels of img it stands for. An image that already fits is only copied.
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Rect, img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, atLeastOne(h*size/b.Dx())
+		} else {
+			w, h = atLeastOne(w*size/b.Dy()), size
+		}
+	}
+	if w == b.Dx() && h == b.Dy() {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*b.Dy()/h, (y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			x0, x1 := x*b.Dx()/w, (x+1)*b.Dx()/w
+			// The channels are premultiplied by alpha, hence averaged as
+			// they are.
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			p := dst.Pix[y*dst.Stride+x*4:]
+			for i := range sum {
+				p[i] = uint8((sum[i] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// atLeastOne returns n, or 1 if n is less, for the sides of the thumbnails.
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package upload
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		w, h, size int
+		want       image.Rectangle
+	}{
+		{640, 480, 160, image.Rect(0, 0, 160, 120)},
+		{480, 640, 160, image.Rect(0, 0, 120, 160)},
+		{1000, 2, 160, image.Rect(0, 0, 160, 1)},
+		{100, 50, 160, image.Rect(0, 0, 100, 50)},
+	}
+	for _, test := range tests {
+		img := image.NewGray(image.Rect(10, 10, 10+test.w, 10+test.h))
+		if got := Thumbnail(img, test.size).Bounds(); got != test.want {
+			t.Errorf("Thumbnail(%dx%d, %d) bounds = %v, want %v", test.w, test.h, test.size, got, test.want)
+		}
+	}
+}
+
+func TestThumbnailAverage(t *testing.T) {
+	// Black and white columns, averaged two by two into gray.
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.NRGBA{A: 0xff}
+			if x%2 == 1 {
+				c = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	thumb := Thumbnail(img, 4)
+	want := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if got := thumb.RGBAAt(x, y); got != want {
+				t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
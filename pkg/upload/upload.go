@@ -0,0 +1,164 @@
+// Package upload keeps the images pasted into the 0B3A-textarea and
+// 0B3B-textarea demos: the chunked uploads of the app and their Progress,
+// the API and the store the server keeps them with, on disk, their
+// thumbnails, and the Gallery page listing them.
+//
+// The app sends the images in chunks of ChunkSize, and the server only keeps
+// PNG, JPEG and GIF images, of MaxSize at most. They are named after the
+// SHA-256 of their content, so that the same image pasted twice is stored
+// once, and their URLs never change content. The store and the API only exist
+// on the server-side: Store and Handler are not built for the web browser.
+package upload
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/suntong/go-app-demos/pkg/jsonapi"
+)
+
+const (
+	// APIPath is the path the API is served under: see Handler.
+	APIPath = "/api/uploads/"
+
+	// FilesPath is the path the images and their thumbnails are served
+	// under: see Files.
+	FilesPath = "/uploads/"
+
+	// GalleryRoute is the route of the gallery of the uploads.
+	GalleryRoute = "/gallery"
+
+	// MaxSize is the maximum size of an image, in bytes.
+	MaxSize = 10 << 20
+
+	// MaxPixels is the maximum number of pixels of an image, which the server
+	// decodes to make its thumbnail.
+	MaxPixels = 25_000_000
+
+	// ChunkSize is the maximum size of the chunks an image is uploaded in.
+	ChunkSize = 256 << 10
+
+	// ThumbSize is the size of the square the thumbnails fit in, in pixels.
+	ThumbSize = 160
+
+	// thumbSuffix ends the names of the thumbnails, PNG images whatever the
+	// type of their image.
+	thumbSuffix = ".thumb.png"
+)
+
+var (
+	// ErrNotFound is returned for uploads, and upload sessions, that do not
+	// exist.
+	ErrNotFound = errors.New("upload not found")
+
+	// ErrInvalid is wrapped by the errors of the images that cannot be
+	// uploaded, such as one too large.
+	ErrInvalid = errors.New("invalid upload")
+
+	// ErrBusy is returned when the server has too many uploads going on to
+	// start another one.
+	ErrBusy = errors.New("too many uploads, try again later")
+)
+
+// api answers the errors of the API, and turns them back into errors in
+// Client.
+var api = jsonapi.API{
+	Name: "upload API",
+	Statuses: []jsonapi.Status{
+		{Err: ErrNotFound, Code: http.StatusNotFound},
+		{Err: ErrInvalid, Code: http.StatusBadRequest},
+		{Err: ErrBusy, Code: http.StatusServiceUnavailable},
+	},
+}
+
+// Exts are the file extensions of the image types that can be uploaded, by
+// MIME type. The server decodes the images, which it can in pure Go.
+var Exts = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// Upload is an uploaded image.
+type Upload struct {
+	// The hexadecimal SHA-256 of the image.
+	ID string `json:"id"`
+
+	Type   string `json:"type"`
+	Size   int64  `json:"size"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+
+	Created time.Time `json:"created"`
+}
+
+// Name returns the file name of the image: its ID, with the extension of its
+// type.
+func (u Upload) Name() string {
+	return u.ID + Exts[u.Type]
+}
+
+// URL returns the path the image is served at.
+func (u Upload) URL() string {
+	return FilesPath + u.Name()
+}
+
+// ThumbURL returns the path the thumbnail of the image is served at.
+func (u Upload) ThumbURL() string {
+	return FilesPath + u.ID + thumbSuffix
+}
+
+// Session is an upload in progress, which the image is sent to chunk by
+// chunk.
+type Session struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+	Received int64  `json:"received"`
+
+	// The upload, once the image is complete.
+	Upload *Upload `json:"upload,omitempty"`
+}
+
+// Request is the image an upload session is started for.
+type Request struct {
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+}
+
+// Validate returns an error wrapping ErrInvalid when the image of r cannot be
+// uploaded.
+func (r Request) Validate() error {
+	switch {
+	case Exts[r.Type] == "":
+		return invalid("unsupported type " + r.Type)
+	case r.Size <= 0:
+		return invalid("empty image")
+	case r.Size > MaxSize:
+		return invalid("image larger than 10 MiB")
+	}
+	return nil
+}
+
+func invalid(reason string) error {
+	return jsonapi.Wrap(ErrInvalid, reason)
+}
+
+// chunkAt returns the chunk of data at offset off: ChunkSize bytes, or those
+// left.
+func chunkAt(data []byte, off int) []byte {
+	end := off + ChunkSize
+	if end > len(data) {
+		end = len(data)
+	}
+	return data[off:end]
+}
+
+var idPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ValidID reports whether id has the form of an upload ID.
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}